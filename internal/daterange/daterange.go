@@ -0,0 +1,197 @@
+// Package daterange interprets timewarrior's range vocabulary (":week",
+// ":lastmonth", "2025-01-01 - 2025-02-01", "from X to Y", ...) so lume can
+// resolve a report range when timew is not there to do it.
+package daterange
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// Range is a half-open [Start, End) interval. The zero Range is unbounded and
+// is what :all resolves to.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsZero reports whether r is unbounded.
+func (r Range) IsZero() bool {
+	return r.Start.IsZero() && r.End.IsZero()
+}
+
+// Parse extracts a range from args and returns it together with the arguments
+// that were not part of it (tags, in timew's grammar). Without any range
+// argument the current day is used, matching timew's own default.
+//
+// Supported forms are the :hints listed in Hint, "X - Y", "X to Y",
+// "from X [to Y]", "since X" and a lone "X", where a lone or open-ended
+// start runs until now. As in timew, any argument that reads as a date is
+// one, so words such as today, now or mon are never returned as tags.
+func Parse(args []string, now time.Time, weekStart time.Weekday) (Range, []string, error) {
+	r, _ := Hint(":day", now, weekStart)
+	found := false
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if strings.HasPrefix(arg, ":") {
+			hint, ok := Hint(arg, now, weekStart)
+			if !ok {
+				return Range{}, nil, fmt.Errorf("unknown range hint %q", arg)
+			}
+			if found {
+				return Range{}, nil, fmt.Errorf("more than one range given (%q)", arg)
+			}
+			r, found = hint, true
+			continue
+		}
+
+		keyword := strings.ToLower(arg)
+		if keyword == "from" || keyword == "since" {
+			if i+1 >= len(args) {
+				return Range{}, nil, fmt.Errorf("%q needs a date", arg)
+			}
+			i++
+		}
+
+		start, ok := parseDatetime(args[i], now)
+		if !ok {
+			if keyword == "from" || keyword == "since" {
+				return Range{}, nil, fmt.Errorf("invalid date %q", args[i])
+			}
+			rest = append(rest, arg)
+			continue
+		}
+		if found {
+			return Range{}, nil, fmt.Errorf("more than one range given (%q)", args[i])
+		}
+
+		end := now
+		if i+2 < len(args) && (args[i+1] == "-" || strings.EqualFold(args[i+1], "to")) && keyword != "since" {
+			end, ok = parseDatetime(args[i+2], now)
+			if !ok {
+				return Range{}, nil, fmt.Errorf("invalid date %q", args[i+2])
+			}
+			i += 2
+		}
+		if !end.After(start) {
+			return Range{}, nil, fmt.Errorf("range end %s is not after its start %s",
+				end.Format("2006-01-02 15:04"), start.Format("2006-01-02 15:04"))
+		}
+		r, found = Range{Start: start, End: end}, true
+	}
+
+	return r, rest, nil
+}
+
+// Hint resolves a single timewarrior range hint relative to now. Weeks (and
+// fortnights) begin on weekStart. ok is false for unknown hints.
+//
+// Known hints: :all, :day/:today, :yesterday, :week, :lastweek, :fortnight,
+// :lastfortnight, :month, :lastmonth, :quarter, :lastquarter, :year,
+// :lastyear, :ytd, and :monday through :sunday (the most recent such day).
+func Hint(hint string, now time.Time, weekStart time.Weekday) (Range, bool) {
+	today := startOfDay(now)
+	week := startOfWeek(today, weekStart)
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	quarter := time.Date(today.Year(), today.Month()-(today.Month()-1)%3, 1, 0, 0, 0, 0, today.Location())
+	year := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+
+	switch strings.ToLower(hint) {
+	case ":all":
+		return Range{}, true
+	case ":day", ":today":
+		return Range{Start: today, End: today.AddDate(0, 0, 1)}, true
+	case ":yesterday":
+		return Range{Start: today.AddDate(0, 0, -1), End: today}, true
+	case ":week":
+		return Range{Start: week, End: week.AddDate(0, 0, 7)}, true
+	case ":lastweek":
+		return Range{Start: week.AddDate(0, 0, -7), End: week}, true
+	case ":fortnight":
+		return Range{Start: week.AddDate(0, 0, -7), End: week.AddDate(0, 0, 7)}, true
+	case ":lastfortnight":
+		return Range{Start: week.AddDate(0, 0, -21), End: week.AddDate(0, 0, -7)}, true
+	case ":month":
+		return Range{Start: month, End: month.AddDate(0, 1, 0)}, true
+	case ":lastmonth":
+		return Range{Start: month.AddDate(0, -1, 0), End: month}, true
+	case ":quarter":
+		return Range{Start: quarter, End: quarter.AddDate(0, 3, 0)}, true
+	case ":lastquarter":
+		return Range{Start: quarter.AddDate(0, -3, 0), End: quarter}, true
+	case ":year":
+		return Range{Start: year, End: year.AddDate(1, 0, 0)}, true
+	case ":lastyear":
+		return Range{Start: year.AddDate(-1, 0, 0), End: year}, true
+	case ":ytd":
+		return Range{Start: year, End: today.AddDate(0, 0, 1)}, true
+	}
+
+	if day, ok := timewarrior.ParseWeekday(strings.TrimPrefix(hint, ":")); ok {
+		start := startOfWeek(today, day)
+		return Range{Start: start, End: start.AddDate(0, 0, 1)}, true
+	}
+	return Range{}, false
+}
+
+var datetimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+}
+
+// parseDatetime understands the absolute and relative date forms timew
+// accepts on the command line: ISO dates (optionally with a time), the
+// compact UTC form timew stores ("20250101T090000Z"), a bare time of day, and
+// the names now, today, yesterday, tomorrow and monday–sunday.
+func parseDatetime(s string, now time.Time) (time.Time, bool) {
+	loc := now.Location()
+	today := startOfDay(now)
+
+	switch strings.ToLower(s) {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+	if day, ok := timewarrior.ParseWeekday(s); ok {
+		return startOfWeek(today, day), true
+	}
+
+	for _, layout := range datetimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t.In(loc), true
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return time.Date(today.Year(), today.Month(), today.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the latest date on or before day that falls on
+// weekStart, which is also how "monday" and ":monday" resolve.
+func startOfWeek(day time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
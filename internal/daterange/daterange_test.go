@@ -0,0 +1,142 @@
+package daterange
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t
+		}
+	}
+	panic("bad test date " + s)
+}
+
+func TestHint(t *testing.T) {
+	wednesday := date("2025-03-12 15:30")
+	tests := []struct {
+		hint       string
+		now        time.Time
+		weekStart  time.Weekday
+		start, end string
+	}{
+		{":day", wednesday, time.Monday, "2025-03-12", "2025-03-13"},
+		{":today", wednesday, time.Monday, "2025-03-12", "2025-03-13"},
+		{":yesterday", wednesday, time.Monday, "2025-03-11", "2025-03-12"},
+		{":week", wednesday, time.Monday, "2025-03-10", "2025-03-17"},
+		{":WEEK", wednesday, time.Monday, "2025-03-10", "2025-03-17"},
+		{":week", wednesday, time.Sunday, "2025-03-09", "2025-03-16"},
+		{":lastweek", wednesday, time.Monday, "2025-03-03", "2025-03-10"},
+		{":fortnight", wednesday, time.Monday, "2025-03-03", "2025-03-17"},
+		{":lastfortnight", wednesday, time.Monday, "2025-02-17", "2025-03-03"},
+		{":month", wednesday, time.Monday, "2025-03-01", "2025-04-01"},
+		{":lastmonth", wednesday, time.Monday, "2025-02-01", "2025-03-01"},
+		{":quarter", wednesday, time.Monday, "2025-01-01", "2025-04-01"},
+		{":lastquarter", wednesday, time.Monday, "2024-10-01", "2025-01-01"},
+		{":year", wednesday, time.Monday, "2025-01-01", "2026-01-01"},
+		{":lastyear", wednesday, time.Monday, "2024-01-01", "2025-01-01"},
+		{":ytd", wednesday, time.Monday, "2025-01-01", "2025-03-13"},
+		{":monday", wednesday, time.Sunday, "2025-03-10", "2025-03-11"},
+		{":wednesday", wednesday, time.Monday, "2025-03-12", "2025-03-13"},
+		{":thu", wednesday, time.Monday, "2025-03-06", "2025-03-07"},
+
+		// Week boundaries: the first and last moments of a week.
+		{":week", date("2025-03-10 00:00"), time.Monday, "2025-03-10", "2025-03-17"},
+		{":week", date("2025-03-16 23:59"), time.Monday, "2025-03-10", "2025-03-17"},
+		{":week", date("2025-03-16 00:00"), time.Sunday, "2025-03-16", "2025-03-23"},
+		{":week", date("2025-03-15 23:59"), time.Sunday, "2025-03-09", "2025-03-16"},
+		{":lastweek", date("2025-01-01 12:00"), time.Monday, "2024-12-23", "2024-12-30"},
+
+		// Quarter boundaries.
+		{":quarter", date("2025-03-31 23:59"), time.Monday, "2025-01-01", "2025-04-01"},
+		{":quarter", date("2025-04-01 00:00"), time.Monday, "2025-04-01", "2025-07-01"},
+		{":lastquarter", date("2025-04-01 00:00"), time.Monday, "2025-01-01", "2025-04-01"},
+		{":lastquarter", date("2025-07-15 12:00"), time.Monday, "2025-04-01", "2025-07-01"},
+		{":quarter", date("2025-12-31 12:00"), time.Monday, "2025-10-01", "2026-01-01"},
+		{":lastquarter", date("2025-01-01 00:00"), time.Monday, "2024-10-01", "2025-01-01"},
+		{":lastmonth", date("2025-03-31 12:00"), time.Monday, "2025-02-01", "2025-03-01"},
+		{":lastmonth", date("2025-01-15 12:00"), time.Monday, "2024-12-01", "2025-01-01"},
+	}
+	for _, tt := range tests {
+		got, ok := Hint(tt.hint, tt.now, tt.weekStart)
+		want := Range{Start: date(tt.start), End: date(tt.end)}
+		if !ok || !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+			t.Errorf("Hint(%q) at %s (weeks from %s) = %v - %v, %v, want %v - %v",
+				tt.hint, tt.now.Format("2006-01-02 15:04"), tt.weekStart, got.Start, got.End, ok, want.Start, want.End)
+		}
+	}
+
+	if r, ok := Hint(":all", wednesday, time.Monday); !ok || !r.IsZero() {
+		t.Errorf("Hint(:all) = %v, %v, want an unbounded range", r, ok)
+	}
+	if _, ok := Hint(":someday", wednesday, time.Monday); ok {
+		t.Error("Hint(:someday) succeeded, want unknown")
+	}
+}
+
+func TestParse(t *testing.T) {
+	now := date("2025-03-12 15:30")
+	tests := []struct {
+		name       string
+		args       []string
+		start, end string
+		rest       []string
+	}{
+		{"default is today", nil, "2025-03-12", "2025-03-13", nil},
+		{"hint and tags", []string{":week", "dev", "project:acme"}, "2025-03-10", "2025-03-17", []string{"dev", "project:acme"}},
+		{"tags before the hint", []string{"dev", ":month"}, "2025-03-01", "2025-04-01", []string{"dev"}},
+		{"tags alone", []string{"dev"}, "2025-03-12", "2025-03-13", []string{"dev"}},
+		{"X - Y", []string{"2025-01-01", "-", "2025-02-01"}, "2025-01-01", "2025-02-01", nil},
+		{"X to Y", []string{"2025-01-01", "TO", "2025-02-01"}, "2025-01-01", "2025-02-01", nil},
+		{"from X to Y", []string{"from", "2025-01-01", "to", "2025-02-01"}, "2025-01-01", "2025-02-01", nil},
+		{"from X", []string{"from", "2025-01-01"}, "2025-01-01", "2025-03-12 15:30", nil},
+		{"since X", []string{"since", "monday"}, "2025-03-10", "2025-03-12 15:30", nil},
+		{"lone X", []string{"2025-03-01", "dev"}, "2025-03-01", "2025-03-12 15:30", []string{"dev"}},
+		{"month", []string{"2025-02", "-", "2025-03"}, "2025-02-01", "2025-03-01", nil},
+		{"date and time of day", []string{"2025-03-12T09:00", "-", "11:30"}, "2025-03-12 09:00", "2025-03-12 11:30", nil},
+		{"compact UTC", []string{"20250312T090000Z", "-", "now"}, "2025-03-12 09:00", "2025-03-12 15:30", nil},
+		{"relative names", []string{"yesterday", "-", "tomorrow"}, "2025-03-11", "2025-03-13", nil},
+		{"weekday names are dates, not tags", []string{"mon", "dev"}, "2025-03-10", "2025-03-12 15:30", []string{"dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, rest, err := Parse(tt.args, now, time.Monday)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.args, err)
+			}
+			if !r.Start.Equal(date(tt.start)) || !r.End.Equal(date(tt.end)) {
+				t.Errorf("Parse(%q) = %v - %v, want %s - %s", tt.args, r.Start, r.End, tt.start, tt.end)
+			}
+			if !slices.Equal(rest, tt.rest) {
+				t.Errorf("Parse(%q) rest = %q, want %q", tt.args, rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := date("2025-03-12 15:30")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{":nope"}, `unknown range hint ":nope"`},
+		{[]string{":week", ":month"}, `more than one range given (":month")`},
+		{[]string{":week", "2025-01-01"}, `more than one range given ("2025-01-01")`},
+		{[]string{"from"}, `"from" needs a date`},
+		{[]string{"since", "soon"}, `invalid date "soon"`},
+		{[]string{"2025-01-01", "-", "soon"}, `invalid date "soon"`},
+		{[]string{"2025-02-01", "-", "2025-01-01"}, "range end 2025-01-01 00:00 is not after its start 2025-02-01 00:00"},
+		{[]string{"tomorrow"}, "range end 2025-03-12 15:30 is not after its start 2025-03-13 00:00"},
+	}
+	for _, tt := range tests {
+		_, _, err := Parse(tt.args, now, time.Monday)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package filter implements lume's report filter expressions, which select
+// entries by more than timew's tags and range:
+//
+//	project:acme and (dev or review) and not desc~"standup" and duration>10m
+//
+// Terms are combined with and, or, not and parentheses; and binds tighter
+// than or. A term is one of:
+//
+//	dev, "code review"     the entry carries the tag
+//	project:acme           the project is acme or below it (acme.api, ...)
+//	desc:"fix login"       the description equals the value (also description:)
+//	weekday:mon            the entry starts on that weekday
+//	tag:dev                the entry carries the tag
+//	client:acme            the entry carries the key:value tag client:acme
+//	field~pattern          field matches a case-insensitive regular expression;
+//	                       fields are desc, project, tag, annotation or any
+//	                       key:value tag key
+//	duration>10m           compare the entry's duration with >, >=, <, <=, =
+package filter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// Expr is a parsed filter expression.
+type Expr interface {
+	Match(e timewarrior.Entry) bool
+}
+
+// Apply returns the entries matching expr. Each is tested as prepare
+// returns it, such as after rules have run, but kept as it was. A nil expr
+// matches everything and a nil prepare tests entries as they are.
+func Apply(expr Expr, entries []timewarrior.Entry, prepare func(timewarrior.Entry) timewarrior.Entry) []timewarrior.Entry {
+	if expr == nil {
+		return entries
+	}
+	var matched []timewarrior.Entry
+	for _, e := range entries {
+		tested := e
+		if prepare != nil {
+			tested = prepare(e)
+		}
+		if expr.Match(tested) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+type andExpr struct{ left, right Expr }
+
+func (x andExpr) Match(e timewarrior.Entry) bool { return x.left.Match(e) && x.right.Match(e) }
+
+type orExpr struct{ left, right Expr }
+
+func (x orExpr) Match(e timewarrior.Entry) bool { return x.left.Match(e) || x.right.Match(e) }
+
+type notExpr struct{ expr Expr }
+
+func (x notExpr) Match(e timewarrior.Entry) bool { return !x.expr.Match(e) }
+
+type tagTerm struct{ tag string }
+
+func (x tagTerm) Match(e timewarrior.Entry) bool { return slices.Contains(e.Tags, x.tag) }
+
+type projectTerm struct{ project string }
+
+func (x projectTerm) Match(e timewarrior.Entry) bool { return e.InProject(x.project) }
+
+type descriptionTerm struct{ description string }
+
+func (x descriptionTerm) Match(e timewarrior.Entry) bool {
+	return strings.EqualFold(e.Description, x.description)
+}
+
+type weekdayTerm struct{ weekday time.Weekday }
+
+func (x weekdayTerm) Match(e timewarrior.Entry) bool { return e.Start.Weekday() == x.weekday }
+
+// patternTerm matches a regular expression against one or more values of a
+// field; it holds when any of them matches.
+type patternTerm struct {
+	field string
+	re    *regexp.Regexp
+}
+
+func (x patternTerm) Match(e timewarrior.Entry) bool {
+	for _, value := range fieldValues(e, x.field) {
+		if x.re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+type durationTerm struct {
+	op       string
+	duration time.Duration
+}
+
+func (x durationTerm) Match(e timewarrior.Entry) bool {
+	d := e.Duration()
+	switch x.op {
+	case ">":
+		return d > x.duration
+	case ">=":
+		return d >= x.duration
+	case "<":
+		return d < x.duration
+	case "<=":
+		return d <= x.duration
+	default:
+		return d == x.duration
+	}
+}
+
+// fieldValues returns the values a pattern term tests for field.
+func fieldValues(e timewarrior.Entry, field string) []string {
+	switch field {
+	case "desc", "description":
+		return []string{e.Description}
+	case "annotation":
+		return []string{e.Annotation}
+	case "project":
+		return []string{e.Project()}
+	case "tag":
+		return e.Tags
+	}
+	var values []string
+	for _, tag := range e.Tags {
+		if value, ok := strings.CutPrefix(tag, field+":"); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,315 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokTerm
+)
+
+// token is a lexed piece of an expression. Terms carry their field, operator
+// and value; a bare tag has an empty field and operator.
+type token struct {
+	kind  tokenKind
+	pos   int // 1-based column, for error messages
+	field string
+	op    string
+	value string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokLParen:
+		return `"("`
+	case tokRParen:
+		return `")"`
+	case tokAnd:
+		return `"and"`
+	case tokOr:
+		return `"or"`
+	case tokNot:
+		return `"not"`
+	}
+	return fmt.Sprintf("%q", t.field+t.op+t.value)
+}
+
+// Parse parses a filter expression. An empty expression returns a nil Expr,
+// which Apply treats as matching everything.
+func Parse(s string) (Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, nil
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at column %d", tok, tok.pos)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd also accepts terms placed side by side, which read as "and" the
+// way timew combines tags.
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.advance()
+		case tokNot, tokLParen, tokTerm:
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokNot:
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	case tokLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at column %d, found %s", closing.pos, closing)
+		}
+		return expr, nil
+	case tokTerm:
+		return termExpr(tok)
+	}
+	return nil, fmt.Errorf("unexpected %s at column %d", tok, tok.pos)
+}
+
+// termExpr turns a lexed term into its matcher.
+func termExpr(tok token) (Expr, error) {
+	invalid := func(hint string) error {
+		return fmt.Errorf("invalid term %s at column %d (%s)", tok, tok.pos, hint)
+	}
+	field := strings.ToLower(tok.field)
+
+	if field == "" {
+		return tagTerm{tok.value}, nil
+	}
+	if field == "duration" {
+		d, err := time.ParseDuration(tok.value)
+		if err != nil {
+			return nil, invalid("use a duration such as 10m or 1h30m")
+		}
+		switch tok.op {
+		case ">", ">=", "<", "<=", "=", ":":
+			return durationTerm{op: tok.op, duration: d}, nil
+		case "!=":
+			return notExpr{durationTerm{op: "=", duration: d}}, nil
+		}
+		return nil, invalid("compare durations with >, >=, <, <= or =")
+	}
+
+	switch tok.op {
+	case "~":
+		re, err := regexp.Compile("(?i)" + tok.value)
+		if err != nil {
+			return nil, invalid(err.Error())
+		}
+		return patternTerm{field: field, re: re}, nil
+	case ":", "=":
+		return equalTerm(field, tok.value, invalid)
+	case "!=":
+		expr, err := equalTerm(field, tok.value, invalid)
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	return nil, invalid("only duration can be compared with " + tok.op)
+}
+
+func equalTerm(field, value string, invalid func(string) error) (Expr, error) {
+	switch field {
+	case "project":
+		return projectTerm{value}, nil
+	case "desc", "description":
+		return descriptionTerm{value}, nil
+	case "tag":
+		return tagTerm{value}, nil
+	case "weekday":
+		weekday, ok := timewarrior.ParseWeekday(value)
+		if !ok {
+			return nil, invalid("use a weekday name such as mon")
+		}
+		return weekdayTerm{weekday}, nil
+	}
+	return tagTerm{field + ":" + value}, nil
+}
+
+const operatorChars = ":~=!<>"
+
+// lex splits an expression into tokens, always ending with tokEOF.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	i := 0
+
+	// readValue reads a quoted string or a bare word starting at i.
+	readValue := func(stopAtOperator bool) (string, error) {
+		if i < len(runes) && runes[i] == '"' {
+			start := i
+			var b strings.Builder
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return "", fmt.Errorf("unterminated quote at column %d", start+1)
+			}
+			i++
+			return b.String(), nil
+		}
+		start := i
+		for i < len(runes) && !strings.ContainsRune(" \t\n()\"", runes[i]) &&
+			!(stopAtOperator && strings.ContainsRune(operatorChars, runes[i])) {
+			i++
+		}
+		return string(runes[start:i]), nil
+	}
+
+	for {
+		for i < len(runes) && strings.ContainsRune(" \t\n", runes[i]) {
+			i++
+		}
+		if i >= len(runes) {
+			return append(tokens, token{kind: tokEOF, pos: i + 1}), nil
+		}
+
+		pos := i + 1
+		switch runes[i] {
+		case '(':
+			i++
+			tokens = append(tokens, token{kind: tokLParen, pos: pos})
+			continue
+		case ')':
+			i++
+			tokens = append(tokens, token{kind: tokRParen, pos: pos})
+			continue
+		}
+
+		quoted := runes[i] == '"'
+		word, err := readValue(true)
+		if err != nil {
+			return nil, err
+		}
+
+		op := ""
+		for i < len(runes) && strings.ContainsRune(operatorChars, runes[i]) && len(op) < 2 {
+			op += string(runes[i])
+			i++
+		}
+		if op == "" {
+			kind := tokTerm
+			if !quoted {
+				switch strings.ToLower(word) {
+				case "and":
+					kind = tokAnd
+				case "or":
+					kind = tokOr
+				case "not":
+					kind = tokNot
+				}
+			}
+			if kind == tokTerm && word == "" {
+				return nil, fmt.Errorf("empty tag at column %d", pos)
+			}
+			tokens = append(tokens, token{kind: kind, pos: pos, value: word})
+			continue
+		}
+
+		switch op {
+		case ":", "~", "=", "!=", ">", ">=", "<", "<=":
+		default:
+			return nil, fmt.Errorf("unknown operator %q at column %d", op, pos+len([]rune(word)))
+		}
+		if word == "" || quoted {
+			return nil, fmt.Errorf("missing field before %q at column %d", op, pos)
+		}
+		value, err := readValue(false)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			return nil, fmt.Errorf("missing value after %q at column %d", word+op, pos)
+		}
+		tokens = append(tokens, token{kind: tokTerm, pos: pos, field: word, op: op, value: value})
+	}
+}
@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Expr
+	}{
+		{"empty", "  ", nil},
+		{"tag", "dev", tagTerm{"dev"}},
+		{"implicit and", "dev review", andExpr{tagTerm{"dev"}, tagTerm{"review"}}},
+		{"and binds tighter than or", "dev or review and pr",
+			orExpr{tagTerm{"dev"}, andExpr{tagTerm{"review"}, tagTerm{"pr"}}}},
+		{"implicit and binds tighter than or", "dev review or pr",
+			orExpr{andExpr{tagTerm{"dev"}, tagTerm{"review"}}, tagTerm{"pr"}}},
+		{"parentheses", "dev (review or pr)",
+			andExpr{tagTerm{"dev"}, orExpr{tagTerm{"review"}, tagTerm{"pr"}}}},
+		{"not binds tightest", "not dev and pr", andExpr{notExpr{tagTerm{"dev"}}, tagTerm{"pr"}}},
+		{"keywords ignore case", "dev OR Not pr", orExpr{tagTerm{"dev"}, notExpr{tagTerm{"pr"}}}},
+		{"quoted tag", `"code review"`, tagTerm{"code review"}},
+		{"quoted keyword is a tag", `"or"`, tagTerm{"or"}},
+		{"quoted value", `desc:"fix login"`, descriptionTerm{"fix login"}},
+		{"escaped quote", `desc:"say \"hi\""`, descriptionTerm{`say "hi"`}},
+		{"project", "project:acme", projectTerm{"acme"}},
+		{"field is case-insensitive", "Project:acme", projectTerm{"acme"}},
+		{"key:value tag", "client:acme", tagTerm{"client:acme"}},
+		{"not equal", "tag!=dev", notExpr{tagTerm{"dev"}}},
+		{"weekday", "weekday:mon", weekdayTerm{time.Monday}},
+		{"duration", "duration>=1h30m", durationTerm{">=", 90 * time.Minute}},
+		{"duration colon", "duration:10m", durationTerm{":", 10 * time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`desc:"fix`, "unterminated quote at column 6"},
+		{`""`, "empty tag at column 1"},
+		{`dev and ""`, "empty tag at column 9"},
+		{`"" dev`, "empty tag at column 1"},
+		{"dev (review", `expected ")" at column 12, found end of filter`},
+		{"dev )", `unexpected ")" at column 5`},
+		{"dev or", "unexpected end of filter at column 7"},
+		{"dev and or pr", `unexpected "or" at column 9`},
+		{"dev !! pr", `unknown operator "!!" at column 5`},
+		{`"a b":c`, `missing field before ":" at column 1`},
+		{"project:", `missing value after "project:" at column 1`},
+		{"duration>soon", `invalid term "duration>soon" at column 1 (use a duration such as 10m or 1h30m)`},
+		{"duration~1h", `invalid term "duration~1h" at column 1 (compare durations with >, >=, <, <= or =)`},
+		{"dev desc>fix", `invalid term "desc>fix" at column 5 (only duration can be compared with >)`},
+		{"weekday:someday", `invalid term "weekday:someday" at column 1 (use a weekday name such as mon)`},
+		{"desc~(", `missing value after "desc~" at column 1`},
+		{`desc~"["`, "invalid term \"desc~[\" at column 1 (error parsing regexp: missing closing ]: `[`)"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error %q", tt.input, tt.want)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %q, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	monday := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	entries := map[string]timewarrior.Entry{
+		"api": {Start: monday, End: monday.Add(2 * time.Hour), Description: "Fix login",
+			Tags: []string{"dev", "project:acme.api", "client:acme"}, Annotation: "ticket 12"},
+		"web": {Start: monday.AddDate(0, 0, 1), End: monday.AddDate(0, 0, 1).Add(30 * time.Minute), Description: "Standup",
+			Tags: []string{"meetings", "project:acme/web"}},
+		"other": {Start: monday.AddDate(0, 0, 2), End: monday.AddDate(0, 0, 2).Add(time.Hour), Description: "Code review",
+			Tags: []string{"dev", "review", "project:acmecorp"}},
+	}
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"project:acme", []string{"api", "web"}},
+		{"not project:acme", []string{"other"}},
+		{"dev or meetings and project:acmecorp", []string{"api", "other"}},
+		{"(dev or meetings) and project:acmecorp", []string{"other"}},
+		{`desc:"fix LOGIN"`, []string{"api"}},
+		{"desc~^stand", []string{"web"}},
+		{"project~/web$", []string{"web"}},
+		{"annotation~ticket", []string{"api"}},
+		{"client~acme", []string{"api"}},
+		{"tag~^rev", []string{"other"}},
+		{"weekday:tue", []string{"web"}},
+		{"duration>30m", []string{"api", "other"}},
+		{"duration<=30m", []string{"web"}},
+		{"duration!=1h", []string{"api", "web"}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.filter)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.filter, err)
+		}
+		for _, name := range []string{"api", "web", "other"} {
+			want := false
+			for _, w := range tt.want {
+				want = want || w == name
+			}
+			if got := expr.Match(entries[name]); got != want {
+				t.Errorf("%q on %s = %v, want %v", tt.filter, name, got, want)
+			}
+		}
+	}
+}
+
+func TestApplyPrepare(t *testing.T) {
+	entries := []timewarrior.Entry{
+		{Description: "standup"},
+		{Description: "deploy"},
+	}
+	expr, err := Parse("meetings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Apply(expr, entries, nil); len(got) != 0 {
+		t.Errorf("Apply without prepare = %v, want none", got)
+	}
+	categorize := func(e timewarrior.Entry) timewarrior.Entry {
+		if e.Description == "standup" {
+			e.Tags = append(e.Tags, "meetings")
+		}
+		return e
+	}
+	got := Apply(expr, entries, categorize)
+	if len(got) != 1 || got[0].Description != "standup" || len(got[0].Tags) != 0 {
+		t.Errorf("Apply with prepare = %+v, want the standup entry as recorded", got)
+	}
+	if got := Apply(nil, entries, categorize); len(got) != 2 {
+		t.Errorf("Apply(nil) kept %d entries, want 2", len(got))
+	}
+}
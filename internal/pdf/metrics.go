@@ -0,0 +1,66 @@
+package pdf
+
+// Glyph widths of the printable ASCII characters (space to tilde) in
+// thousandths of the font size, from Adobe's Helvetica AFM files.
+var widths = [...][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+		278, 278, 584, 584, 584, 556, 1015, // : to @
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+		278, 278, 278, 469, 556, 333, // [ to `
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+		556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+		334, 260, 334, 584, // { to ~
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+		333, 333, 584, 584, 584, 611, 975, // : to @
+		722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, // A to M
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+		333, 278, 333, 584, 556, 333, // [ to `
+		556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, // a to m
+		611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, // n to z
+		389, 280, 389, 584, // { to ~
+	},
+}
+
+// wideGlyphs holds the widths of the non-ASCII characters that differ from
+// the average glyph width used for the rest.
+var wideGlyphs = map[byte]int{
+	0x85: 1000, // …
+	0x95: 350,  // •
+	0x97: 1000, // —
+	0x99: 1000, // ™
+	0xa0: 278,  // no-break space
+}
+
+// TextWidth returns the width of s in points when set in font at size.
+func TextWidth(font Font, size float64, s string) float64 {
+	total := 0
+	for _, c := range encode(s) {
+		switch {
+		case c >= 32 && c <= 126:
+			total += widths[font][c-32]
+		case wideGlyphs[c] != 0:
+			total += wideGlyphs[c]
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Fit shortens s with an ellipsis until it is at most width points wide.
+func Fit(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(font, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteTo(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	first := doc.AddPage()
+	first.Text(50, 60, HelveticaBold, 12, "Timesheet (draft) \\ 100%")
+	first.Line(50, 70, 545.28, 70, 0.5, 0)
+	first.FillRect(50, 80, 100, 20, 0.9)
+	doc.AddPage().TextRight(545.28, 800, Helvetica, 9, "Page 2")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if n != int64(len(out)) {
+		t.Errorf("WriteTo() = %d, wrote %d bytes", n, len(out))
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+		t.Errorf("header = %q", out[:min(len(out), 16)])
+	}
+	if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Errorf("output does not end with %%%%EOF")
+	}
+
+	// The trailer points at the cross-reference table, whose entries point
+	// at each object in turn.
+	m := regexp.MustCompile(`trailer\n<< /Size (\d+) /Root 1 0 R >>\nstartxref\n(\d+)\n%%EOF\n$`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("no trailer in %q", out[max(0, len(out)-80):])
+	}
+	size, _ := strconv.Atoi(string(m[1]))
+	xref, _ := strconv.Atoi(string(m[2]))
+	if size != 9 {
+		t.Errorf("/Size = %d, want 9 (free entry, catalog, pages, two fonts, two objects per page)", size)
+	}
+	table := string(out[xref:])
+	if !strings.HasPrefix(table, fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", size)) {
+		t.Fatalf("startxref %d does not point at the xref table: %q", xref, table[:min(len(table), 40)])
+	}
+	lines := strings.Split(table, "\n")[3 : 3+size-1]
+	for i, line := range lines {
+		if len(line) != 19 || !strings.HasSuffix(line, " 00000 n ") {
+			t.Fatalf("xref entry %d = %q, want \"nnnnnnnnnn 00000 n \"", i+1, line)
+		}
+		offset, _ := strconv.Atoi(line[:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, out[offset:min(len(out), offset+12)], want)
+		}
+	}
+
+	if !bytes.Contains(out, []byte("/Kids [5 0 R 7 0 R] /Count 2")) {
+		t.Error("page tree does not list both pages")
+	}
+
+	// Each content stream is as long as its /Length says and inflates to the
+	// page's drawing operators.
+	streams := regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(out, -1)
+	if len(streams) != 2 {
+		t.Fatalf("found %d content streams, want 2", len(streams))
+	}
+	var contents []string
+	for _, s := range streams {
+		length, _ := strconv.Atoi(string(out[s[2]:s[3]]))
+		data := out[s[1] : s[1]+length]
+		if !bytes.HasPrefix(out[s[1]+length:], []byte("\nendstream\nendobj\n")) {
+			t.Errorf("stream of /Length %d is not followed by endstream", length)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(content))
+	}
+	wantFirst := "BT /F2 12 Tf 50 781.89 Td (Timesheet \\(draft\\) \\\\ 100%) Tj ET\n" +
+		"0 G 0.5 w 50 771.89 m 545.28 771.89 l S\n" +
+		"0.9 g 50 741.89 100 20 re f 0 g\n"
+	if contents[0] != wantFirst {
+		t.Errorf("first page content =\n%q\nwant\n%q", contents[0], wantFirst)
+	}
+	if !strings.Contains(contents[1], "(Page 2) Tj") {
+		t.Errorf("second page content = %q", contents[1])
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a (b) \\ c", "a \\(b\\) \\\\ c"},
+		{"Müller", "M\\374ller"},
+		{"12 €", "12 \\200"},
+		{"9–5 “ok”", "9\\2265 \\223ok\\224"},
+		{"日本", "??"},
+		{"tab\there", "tab\\011here"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Package billing turns tracked sessions into billable time and money: each
+// session is rounded to an increment and raised to a minimum, then priced at
+// the hourly rate of its project or client.
+//
+// The policy is configured under reports.lume.billing:
+//
+//	reports.lume.billing.rate = 90
+//	reports.lume.billing.increment = 15m
+//	reports.lume.billing.mode = up
+//	reports.lume.billing.minimum = 15m
+//	reports.lume.billing.client.acme.minimum = 1h
+//	reports.lume.billing.currency = EUR
+//	reports.lume.billing.client.acme.rate = 120
+//	reports.lume.billing.project.acme.support.rate = 80
+//	reports.lume.billing.tax.VAT = 19%
+//	reports.lume.billing.default = billable
+//
+// A session's client is its client:<name> tag, else the first level of its
+// project (acme for project:acme.api). Sessions tagged nonbillable are never
+// billed, sessions tagged billable always are, and untagged ones follow
+// the default. Sessions tagged invoiced have already been billed.
+package billing
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+const keyPrefix = "reports.lume.billing."
+
+// Tags that mark a session's billing status.
+const (
+	TagBillable    = "billable"
+	TagNonBillable = "nonbillable"
+	TagInvoiced    = "invoiced"
+)
+
+// IsMarker reports whether tag is one of the billing status tags, which
+// describe a session rather than categorize it.
+func IsMarker(tag string) bool {
+	return tag == TagBillable || tag == TagNonBillable || tag == TagInvoiced
+}
+
+// Mode is the direction a session is rounded in.
+type Mode string
+
+const (
+	RoundUp      Mode = "up"
+	RoundNearest Mode = "nearest"
+	RoundDown    Mode = "down"
+)
+
+// Policy is the billing rounding and pricing applied to every session.
+type Policy struct {
+	Increment      time.Duration
+	Mode           Mode
+	Minimum        time.Duration
+	ClientMinimums map[string]time.Duration
+
+	// Currency labels amounts (EUR, USD, ...); rates are in cents of it per
+	// hour.
+	Currency     string
+	Rate         int64
+	ClientRates  map[string]int64
+	ProjectRates map[string]int64
+	Taxes        []Tax
+	// BillableByDefault decides sessions tagged neither billable nor
+	// nonbillable.
+	BillableByDefault bool
+}
+
+// Tax is a tax line added on top of the billable amount.
+type Tax struct {
+	Name    string
+	Percent float64
+}
+
+// FromConfig reads the billing policy from cfg. It returns nil when no
+// billing key is set; a nil Policy bills exactly the tracked time. Once any
+// billing key is set, the default rate must be too, so that no session is
+// priced at zero by accident; a rate of 0 only rounds billed time.
+func FromConfig(cfg timewarrior.TimewConfig) (*Policy, error) {
+	configured := false
+	for key := range cfg.Values {
+		if strings.HasPrefix(key, keyPrefix) {
+			configured = true
+			break
+		}
+	}
+	if !configured {
+		return nil, nil
+	}
+
+	p := &Policy{
+		Mode:              RoundUp,
+		ClientMinimums:    make(map[string]time.Duration),
+		Currency:          strings.TrimSpace(cfg.Get(keyPrefix + "currency")),
+		ClientRates:       make(map[string]int64),
+		ProjectRates:      make(map[string]int64),
+		BillableByDefault: true,
+	}
+	var err error
+	if p.Increment, err = parseDuration(cfg, keyPrefix+"increment"); err != nil {
+		return nil, err
+	}
+	if p.Minimum, err = parseDuration(cfg, keyPrefix+"minimum"); err != nil {
+		return nil, err
+	}
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(cfg.Get(keyPrefix + "mode")))); mode {
+	case "":
+	case RoundUp, RoundNearest, RoundDown:
+		p.Mode = mode
+	default:
+		return nil, fmt.Errorf("invalid %smode %q (use up, nearest or down)", keyPrefix, mode)
+	}
+
+	switch v := strings.ToLower(strings.TrimSpace(cfg.Get(keyPrefix + "default"))); v {
+	case "", TagBillable:
+	case TagNonBillable:
+		p.BillableByDefault = false
+	default:
+		return nil, fmt.Errorf("invalid %sdefault %q (use billable or nonbillable)", keyPrefix, v)
+	}
+	v := cfg.Get(keyPrefix + "rate")
+	if strings.TrimSpace(v) == "" {
+		return nil, fmt.Errorf("missing %srate (set the default hourly rate, or 0 to bill time without amounts)", keyPrefix)
+	}
+	if p.Rate, err = parseMoney(keyPrefix+"rate", v); err != nil {
+		return nil, err
+	}
+
+	for key, value := range cfg.Values {
+		rest, ok := strings.CutPrefix(key, keyPrefix)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(rest, "client.") && strings.HasSuffix(rest, ".minimum"):
+			client := strings.TrimSuffix(strings.TrimPrefix(rest, "client."), ".minimum")
+			if client != "" {
+				if p.ClientMinimums[client], err = parseDuration(cfg, key); err != nil {
+					return nil, err
+				}
+			}
+		case strings.HasPrefix(rest, "client.") && strings.HasSuffix(rest, ".rate"):
+			client := strings.TrimSuffix(strings.TrimPrefix(rest, "client."), ".rate")
+			if client != "" {
+				if p.ClientRates[client], err = parseMoney(key, value); err != nil {
+					return nil, err
+				}
+			}
+		case strings.HasPrefix(rest, "project.") && strings.HasSuffix(rest, ".rate"):
+			project := strings.TrimSuffix(strings.TrimPrefix(rest, "project."), ".rate")
+			if project != "" {
+				if p.ProjectRates[project], err = parseMoney(key, value); err != nil {
+					return nil, err
+				}
+			}
+		case strings.HasPrefix(rest, "tax."):
+			name := strings.TrimPrefix(rest, "tax.")
+			percent, err := parseAmount(key, strings.TrimSuffix(strings.TrimSpace(value), "%"))
+			if err != nil {
+				return nil, err
+			}
+			if name != "" {
+				p.Taxes = append(p.Taxes, Tax{Name: name, Percent: percent})
+			}
+		}
+	}
+	sort.Slice(p.Taxes, func(i, j int) bool { return p.Taxes[i].Name < p.Taxes[j].Name })
+	return p, nil
+}
+
+func parseAmount(key, v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid %s %q (use a non-negative number such as 90 or 7.5)", key, v)
+	}
+	return f, nil
+}
+
+// parseMoney parses an amount such as 90 or 80.50 into cents, rounding any
+// finer fraction to the nearest cent.
+func parseMoney(key, v string) (int64, error) {
+	f, err := parseAmount(key, v)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(f * 100)), nil
+}
+
+func parseDuration(cfg timewarrior.TimewConfig, key string) (time.Duration, error) {
+	v := strings.TrimSpace(cfg.Get(key))
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q (use a duration such as 6m, 15m or 1h)", key, v)
+	}
+	return d, nil
+}
+
+// Bill returns the billable duration of one session: its tracked time
+// rounded to the increment in the policy's direction, then raised to the
+// client's minimum (or the default minimum). A nil Policy returns the
+// tracked time unchanged.
+func (p *Policy) Bill(e timewarrior.Entry) time.Duration {
+	tracked := e.Duration().Truncate(time.Second)
+	if p == nil || tracked <= 0 {
+		return tracked
+	}
+
+	billed := tracked
+	if p.Increment > 0 {
+		switch p.Mode {
+		case RoundDown:
+			billed = tracked.Truncate(p.Increment)
+		case RoundNearest:
+			billed = tracked.Round(p.Increment)
+		default:
+			billed = tracked.Truncate(p.Increment)
+			if billed < tracked {
+				billed += p.Increment
+			}
+		}
+	}
+
+	minimum := p.Minimum
+	if clientMinimum, ok := p.ClientMinimums[Client(e)]; ok {
+		minimum = clientMinimum
+	}
+	return max(billed, minimum)
+}
+
+// Client returns the client a session is billed to: its client:<name> tag,
+// else the first level of its project, else "".
+func Client(e timewarrior.Entry) string {
+	for _, tag := range e.Tags {
+		if name, ok := strings.CutPrefix(tag, "client:"); ok && name != "" {
+			return name
+		}
+	}
+	client, _, _ := strings.Cut(e.Project(), ".")
+	client, _, _ = strings.Cut(client, "/")
+	return client
+}
+
+// Billable reports whether a session is billed at all: a billable or
+// nonbillable tag decides, else the policy's default. Every session is
+// billable under a nil Policy.
+func (p *Policy) Billable(e timewarrior.Entry) bool {
+	switch {
+	case slices.Contains(e.Tags, TagNonBillable):
+		return false
+	case slices.Contains(e.Tags, TagBillable):
+		return true
+	}
+	return p == nil || p.BillableByDefault
+}
+
+// Invoiced reports whether a session has already been billed.
+func Invoiced(e timewarrior.Entry) bool {
+	return slices.Contains(e.Tags, TagInvoiced)
+}
+
+// RateFor returns the hourly rate for time on project for client: the rate of
+// the project or its nearest parent (acme.api, then acme), else the client's
+// rate, else the default rate.
+func (p *Policy) RateFor(client, project string) int64 {
+	if p == nil {
+		return 0
+	}
+	for name := project; name != ""; {
+		if rate, ok := p.ProjectRates[name]; ok {
+			return rate
+		}
+		i := strings.LastIndexAny(name, "./")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	if rate, ok := p.ClientRates[client]; ok {
+		return rate
+	}
+	return p.Rate
+}
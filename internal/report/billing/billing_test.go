@@ -0,0 +1,196 @@
+package billing
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestFromConfig(t *testing.T) {
+	cfg := timewarrior.TimewConfig{Values: map[string]string{
+		"reports.lume.billing.rate":                      "90",
+		"reports.lume.billing.increment":                 "15m",
+		"reports.lume.billing.mode":                      "Nearest",
+		"reports.lume.billing.minimum":                   "30m",
+		"reports.lume.billing.client.acme.minimum":       "1h",
+		"reports.lume.billing.currency":                  " EUR ",
+		"reports.lume.billing.client.acme.rate":          "120.505",
+		"reports.lume.billing.project.acme.support.rate": "80",
+		"reports.lume.billing.tax.VAT":                   "19%",
+		"reports.lume.billing.tax.city":                  "0.5",
+		"reports.lume.billing.default":                   "nonbillable",
+		"reports.lume.other":                             "x",
+	}}
+	p, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rate != 9000 || p.Increment != 15*time.Minute || p.Mode != RoundNearest || p.Minimum != 30*time.Minute {
+		t.Errorf("policy = %+v", p)
+	}
+	if p.Currency != "EUR" || p.BillableByDefault {
+		t.Errorf("Currency, BillableByDefault = %q, %t, want EUR, false", p.Currency, p.BillableByDefault)
+	}
+	if want := map[string]time.Duration{"acme": time.Hour}; !maps.Equal(p.ClientMinimums, want) {
+		t.Errorf("ClientMinimums = %v, want %v", p.ClientMinimums, want)
+	}
+	if want := map[string]int64{"acme": 12051}; !maps.Equal(p.ClientRates, want) {
+		t.Errorf("ClientRates = %v, want %v", p.ClientRates, want)
+	}
+	if want := map[string]int64{"acme.support": 8000}; !maps.Equal(p.ProjectRates, want) {
+		t.Errorf("ProjectRates = %v, want %v", p.ProjectRates, want)
+	}
+	if want := []Tax{{"VAT", 19}, {"city", 0.5}}; !slices.Equal(p.Taxes, want) {
+		t.Errorf("Taxes = %v, want %v", p.Taxes, want)
+	}
+}
+
+func TestFromConfigUnset(t *testing.T) {
+	p, err := FromConfig(timewarrior.TimewConfig{Values: map[string]string{"reports.lume.format": "json"}})
+	if p != nil || err != nil {
+		t.Errorf("FromConfig() = %+v, %v, want nil, nil", p, err)
+	}
+}
+
+func TestFromConfigZeroRate(t *testing.T) {
+	p, err := FromConfig(timewarrior.TimewConfig{Values: map[string]string{
+		"reports.lume.billing.rate":      "0",
+		"reports.lume.billing.increment": "6m",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rate != 0 || p.Increment != 6*time.Minute || p.Mode != RoundUp || !p.BillableByDefault {
+		t.Errorf("policy = %+v", p)
+	}
+}
+
+func TestFromConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   string
+	}{
+		{"no rate", map[string]string{"reports.lume.billing.increment": "15m"}, "missing reports.lume.billing.rate"},
+		{"only a client rate", map[string]string{"reports.lume.billing.client.acme.rate": "120"}, "missing reports.lume.billing.rate"},
+		{"blank rate", map[string]string{"reports.lume.billing.rate": " "}, "missing reports.lume.billing.rate"},
+		{"negative rate", map[string]string{"reports.lume.billing.rate": "-1"}, "invalid reports.lume.billing.rate"},
+		{"bad increment", map[string]string{"reports.lume.billing.rate": "90", "reports.lume.billing.increment": "15"}, "invalid reports.lume.billing.increment"},
+		{"bad mode", map[string]string{"reports.lume.billing.rate": "90", "reports.lume.billing.mode": "ceil"}, "invalid reports.lume.billing.mode"},
+		{"bad default", map[string]string{"reports.lume.billing.rate": "90", "reports.lume.billing.default": "maybe"}, "invalid reports.lume.billing.default"},
+		{"bad client rate", map[string]string{"reports.lume.billing.rate": "90", "reports.lume.billing.client.acme.rate": "lots"}, "invalid reports.lume.billing.client.acme.rate"},
+		{"bad tax", map[string]string{"reports.lume.billing.rate": "90", "reports.lume.billing.tax.VAT": "x%"}, "invalid reports.lume.billing.tax.VAT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromConfig(timewarrior.TimewConfig{Values: tt.values})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromConfig() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBill(t *testing.T) {
+	start := time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+	session := func(d time.Duration, tags ...string) timewarrior.Entry {
+		return timewarrior.Entry{Start: start, End: start.Add(d), Tags: tags}
+	}
+	tests := []struct {
+		name    string
+		policy  *Policy
+		session timewarrior.Entry
+		want    time.Duration
+	}{
+		{"nil policy", nil, session(7*time.Minute + 1500*time.Millisecond), 7*time.Minute + time.Second},
+		{"no increment", &Policy{}, session(7 * time.Minute), 7 * time.Minute},
+		{"up", &Policy{Increment: 15 * time.Minute}, session(16 * time.Minute), 30 * time.Minute},
+		{"up exact", &Policy{Increment: 15 * time.Minute}, session(30 * time.Minute), 30 * time.Minute},
+		{"nearest", &Policy{Increment: 15 * time.Minute, Mode: RoundNearest}, session(22 * time.Minute), 15 * time.Minute},
+		{"down", &Policy{Increment: 15 * time.Minute, Mode: RoundDown}, session(29 * time.Minute), 15 * time.Minute},
+		{"minimum after rounding", &Policy{Increment: 6 * time.Minute, Mode: RoundDown, Minimum: 15 * time.Minute}, session(5 * time.Minute), 15 * time.Minute},
+		{"client minimum", &Policy{Minimum: 15 * time.Minute, ClientMinimums: map[string]time.Duration{"acme": time.Hour}}, session(5*time.Minute, "project:acme.api"), time.Hour},
+		{"client minimum can be lower", &Policy{Minimum: 15 * time.Minute, ClientMinimums: map[string]time.Duration{"acme": 0}}, session(5*time.Minute, "client:acme"), 5 * time.Minute},
+		{"empty session", &Policy{Minimum: 15 * time.Minute}, session(0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Bill(tt.session); got != tt.want {
+				t.Errorf("Bill() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"project:acme.api"}, "acme"},
+		{[]string{"project:acme/api"}, "acme"},
+		{[]string{"project:acme.api", "client:globex"}, "globex"},
+		{[]string{"client:", "project:acme"}, "acme"},
+		{[]string{"dev"}, ""},
+	}
+	for _, tt := range tests {
+		if got := Client(timewarrior.Entry{Tags: tt.tags}); got != tt.want {
+			t.Errorf("Client(%q) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestBillable(t *testing.T) {
+	byDefault := &Policy{BillableByDefault: true}
+	optIn := &Policy{}
+	tests := []struct {
+		policy *Policy
+		tags   []string
+		want   bool
+	}{
+		{nil, nil, true},
+		{nil, []string{TagNonBillable}, false},
+		{byDefault, nil, true},
+		{byDefault, []string{TagNonBillable}, false},
+		{optIn, nil, false},
+		{optIn, []string{TagBillable}, true},
+		{optIn, []string{TagBillable, TagNonBillable}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Billable(timewarrior.Entry{Tags: tt.tags}); got != tt.want {
+			t.Errorf("Billable(%q) under %+v = %t, want %t", tt.tags, tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestRateFor(t *testing.T) {
+	p := &Policy{
+		Rate:         9000,
+		ClientRates:  map[string]int64{"acme": 12000},
+		ProjectRates: map[string]int64{"acme.support": 8000, "globex": 10000},
+	}
+	tests := []struct {
+		client, project string
+		want            int64
+	}{
+		{"acme", "acme.support", 8000},
+		{"acme", "acme.support.tickets", 8000},
+		{"acme", "acme.support/tickets", 8000},
+		{"acme", "acme.api", 12000},
+		{"globex", "globex.web", 10000},
+		{"initech", "initech", 9000},
+		{"", "", 9000},
+	}
+	for _, tt := range tests {
+		if got := p.RateFor(tt.client, tt.project); got != tt.want {
+			t.Errorf("RateFor(%q, %q) = %d, want %d", tt.client, tt.project, got, tt.want)
+		}
+	}
+	if got := (*Policy)(nil).RateFor("acme", "acme"); got != 0 {
+		t.Errorf("nil RateFor() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,112 @@
+package billing_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/billing"
+	"github.com/amiraminb/lume/internal/report/build"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// TestBillingReportPricesEachTask pins where amounts are rounded: each task
+// is priced to the nearest cent on its own, and the subtotal is the sum of
+// those prices rather than the price of the total time.
+func TestBillingReportPricesEachTask(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, time.March, 10, h, m, 0, 0, time.UTC) }
+	entries := []timewarrior.Entry{
+		{Start: at(9, 0), End: at(9, 20), Description: "a", Tags: []string{"project:acme"}},
+		{Start: at(10, 0), End: at(10, 20), Description: "b", Tags: []string{"project:acme"}},
+		{Start: at(11, 0), End: at(11, 20), Description: "c", Tags: []string{"project:acme", "invoiced"}},
+	}
+	policy := &billing.Policy{Rate: 1000, Taxes: []billing.Tax{{Name: "vat", Percent: 19}}, BillableByDefault: true}
+
+	report := build.BillingReport(entries, at(0, 0), at(23, 0), build.Options{Billing: policy})
+	// Each 20-minute task costs 333.33 cents, priced as 333; an hour at the
+	// same rate would be 1000.
+	if report.Subtotal != 999 {
+		t.Errorf("Subtotal = %d, want 999", report.Subtotal)
+	}
+	if len(report.Taxes) != 1 || report.Taxes[0].Amount != 190 {
+		t.Errorf("Taxes = %+v, want vat of 190", report.Taxes)
+	}
+	if report.Total != 1189 {
+		t.Errorf("Total = %d, want 1189", report.Total)
+	}
+	if report.Invoiced.Amount != 333 || report.Uninvoiced.Amount != 666 {
+		t.Errorf("Invoiced, Uninvoiced = %d, %d, want 333, 666", report.Invoiced.Amount, report.Uninvoiced.Amount)
+	}
+}
+
+// TestBillingReportAddsUp checks, on random sessions at awkward rates, that
+// every line of the invoice adds up to the line above it and to the total.
+func TestBillingReportAddsUp(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	start := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	policy := &billing.Policy{
+		Increment:         6 * time.Minute,
+		Mode:              billing.RoundNearest,
+		Minimum:           15 * time.Minute,
+		Rate:              9000,
+		ClientRates:       map[string]int64{"acme": 12051},
+		ProjectRates:      map[string]int64{"acme.support": 8050, "globex": 123457},
+		Taxes:             []billing.Tax{{Name: "city", Percent: 0.5}, {Name: "vat", Percent: 19}},
+		BillableByDefault: true,
+	}
+	projects := []string{"acme", "acme.api", "acme.support", "globex", "globex.web", "initech"}
+
+	for round := 0; round < 50; round++ {
+		var entries []timewarrior.Entry
+		at := start
+		for i := 0; i < 40; i++ {
+			at = at.Add(time.Duration(rng.Intn(7200)) * time.Second)
+			end := at.Add(time.Duration(1+rng.Intn(4*3600)) * time.Second)
+			tags := []string{"project:" + projects[rng.Intn(len(projects))]}
+			switch rng.Intn(4) {
+			case 0:
+				tags = append(tags, billing.TagInvoiced)
+			case 1:
+				tags = append(tags, billing.TagNonBillable)
+			}
+			entries = append(entries, timewarrior.Entry{
+				Start: at, End: end, Description: fmt.Sprintf("task %d", rng.Intn(5)), Tags: tags,
+			})
+			at = end
+		}
+
+		report := build.BillingReport(entries, time.Time{}, time.Time{}, build.Options{Billing: policy})
+		var subtotal int64
+		for _, c := range report.Clients {
+			var clientAmount int64
+			for _, p := range c.Projects {
+				var projectAmount int64
+				for _, task := range p.Tasks {
+					projectAmount += task.Amount
+				}
+				if projectAmount != p.Amount {
+					t.Fatalf("project %s: tasks add up to %d, want %d", p.Name, projectAmount, p.Amount)
+				}
+				clientAmount += p.Amount
+			}
+			if clientAmount != c.Amount {
+				t.Fatalf("client %s: projects add up to %d, want %d", c.Name, clientAmount, c.Amount)
+			}
+			subtotal += c.Amount
+		}
+		if subtotal != report.Subtotal {
+			t.Fatalf("clients add up to %d, want the subtotal %d", subtotal, report.Subtotal)
+		}
+		if split := report.Invoiced.Amount + report.Uninvoiced.Amount; split != report.Subtotal {
+			t.Fatalf("invoiced and uninvoiced add up to %d, want the subtotal %d", split, report.Subtotal)
+		}
+		total := report.Subtotal
+		for _, tax := range report.Taxes {
+			total += tax.Amount
+		}
+		if total != report.Total {
+			t.Fatalf("subtotal and taxes add up to %d, want the total %d", total, report.Total)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package build
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/billing"
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// Allocation decides how an entry's time is shared out when it carries more
+// than one category tag.
+type Allocation string
+
+const (
+	// AllocateFull counts the whole duration under every tag, so shares can
+	// add up to more than 100%.
+	AllocateFull Allocation = "full"
+	// AllocateSplit divides the duration evenly between the tags; the first
+	// tag takes what does not divide evenly, so the shares add up to it.
+	AllocateSplit Allocation = "split"
+	// AllocatePrimary gives the whole duration to one tag: the first one in
+	// the configured category order, else the entry's first tag.
+	AllocatePrimary Allocation = "primary"
+)
+
+// categoryTags returns the tags that count as categories for ByTag: every
+// tag except key:value dimensions such as the project and the billing status
+// tags, or "untagged" when nothing is left.
+func categoryTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if _, _, ok := model.ParseDimension(tag); !ok && !billing.IsMarker(tag) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) == 0 {
+		return []string{"untagged"}
+	}
+	return result
+}
+
+// allocate shares d between tags according to opts.Allocation.
+func allocate(tags []string, d time.Duration, opts Options) map[string]time.Duration {
+	shares := make(map[string]time.Duration, len(tags))
+	switch opts.Allocation {
+	case AllocateSplit:
+		each := d / time.Duration(len(tags))
+		for _, tag := range tags {
+			shares[tag] += each
+		}
+		shares[tags[0]] += d - each*time.Duration(len(tags))
+	case AllocatePrimary:
+		shares[primaryTag(tags, opts.Categories)] = d
+	default:
+		for _, tag := range tags {
+			shares[tag] = d
+		}
+	}
+	return shares
+}
+
+// primaryTag picks the tag ranked earliest in categories, falling back to the
+// first tag when none of them is a category.
+func primaryTag(tags []string, categories []string) string {
+	best, bestRank := tags[0], len(categories)
+	for _, tag := range tags {
+		if rank := slices.Index(categories, strings.ToLower(tag)); rank >= 0 && rank < bestRank {
+			best, bestRank = tag, rank
+		}
+	}
+	return best
+}
+
+// categoryShares attributes d to the report's category tables. Tags are
+// matched to categories case-insensitively. Under split and primary
+// allocation, the share of a tag that is not a category goes to the fallback,
+// so the shares add up to d. Under full allocation every share is already the
+// whole of d, so the fallback only receives entries matching no category at
+// all: a "dev notes" entry shows in dev but not also in the fallback table.
+func categoryShares(tags []string, d time.Duration, opts Options) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	matched := false
+	for tag, share := range allocate(categoryTags(tags), d, opts) {
+		category := strings.ToLower(tag)
+		switch {
+		case slices.Contains(opts.Categories, category):
+			result[category] += share
+			matched = true
+		case opts.Allocation != AllocateFull && opts.FallbackCategory != "":
+			result[opts.FallbackCategory] += share
+		}
+	}
+	if !matched && opts.Allocation == AllocateFull && opts.FallbackCategory != "" {
+		result[opts.FallbackCategory] += d
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package build
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestAllocate(t *testing.T) {
+	categories := []string{"dev", "meetings", "misc"}
+	tests := []struct {
+		name       string
+		allocation Allocation
+		tags       []string
+		d          time.Duration
+		want       map[string]time.Duration
+	}{
+		{"full", AllocateFull, []string{"dev", "review"}, time.Hour, map[string]time.Duration{"dev": time.Hour, "review": time.Hour}},
+		{"split", AllocateSplit, []string{"dev", "review"}, time.Hour, map[string]time.Duration{"dev": 30 * time.Minute, "review": 30 * time.Minute}},
+		{"split remainder", AllocateSplit, []string{"a", "b", "c"}, 100, map[string]time.Duration{"a": 34, "b": 33, "c": 33}},
+		{"primary by category order", AllocatePrimary, []string{"review", "Meetings", "dev"}, time.Hour, map[string]time.Duration{"dev": time.Hour}},
+		{"primary without a category", AllocatePrimary, []string{"review", "notes"}, time.Hour, map[string]time.Duration{"review": time.Hour}},
+		{"single tag", AllocateSplit, []string{"dev"}, time.Hour, map[string]time.Duration{"dev": time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocate(tt.tags, tt.d, Options{Allocation: tt.allocation, Categories: categories})
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("allocate(%q, %v) = %v, want %v", tt.tags, tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAllocateAddsUp checks that split and primary allocation never lose or
+// invent time, whatever the duration and number of tags.
+func TestAllocateAddsUp(t *testing.T) {
+	tags := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for _, allocation := range []Allocation{AllocateSplit, AllocatePrimary} {
+		for n := 1; n <= len(tags); n++ {
+			for _, d := range []time.Duration{0, 1, 999999999, time.Second, 7*time.Hour + 13*time.Second + 5} {
+				var sum time.Duration
+				for _, share := range allocate(tags[:n], d, Options{Allocation: allocation}) {
+					sum += share
+				}
+				if sum != d {
+					t.Errorf("%s allocation of %v between %d tags adds up to %v", allocation, d, n, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestCategoryShares(t *testing.T) {
+	categories := []string{"dev", "meetings", "misc"}
+	tests := []struct {
+		name       string
+		allocation Allocation
+		fallback   string
+		tags       []string
+		want       map[string]time.Duration
+	}{
+		{"full", AllocateFull, "misc", []string{"dev", "Meetings", "project:acme"}, map[string]time.Duration{"dev": time.Hour, "meetings": time.Hour}},
+		{"full keeps other tags out of the fallback", AllocateFull, "misc", []string{"dev", "notes"}, map[string]time.Duration{"dev": time.Hour}},
+		{"full without a category", AllocateFull, "misc", []string{"notes", "client:acme"}, map[string]time.Duration{"misc": time.Hour}},
+		{"untagged", AllocateFull, "misc", []string{"project:acme"}, map[string]time.Duration{"misc": time.Hour}},
+		{"split sends other tags to the fallback", AllocateSplit, "misc", []string{"dev", "notes"}, map[string]time.Duration{"dev": 30 * time.Minute, "misc": 30 * time.Minute}},
+		{"split without a fallback", AllocateSplit, "", []string{"dev", "notes"}, map[string]time.Duration{"dev": 30 * time.Minute}},
+		{"primary", AllocatePrimary, "misc", []string{"notes", "meetings", "dev"}, map[string]time.Duration{"dev": time.Hour}},
+		{"primary without a category", AllocatePrimary, "misc", []string{"notes", "review"}, map[string]time.Duration{"misc": time.Hour}},
+		{"billing markers are not categories", AllocateSplit, "misc", []string{"dev", "billable"}, map[string]time.Duration{"dev": time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Allocation: tt.allocation, Categories: categories, FallbackCategory: tt.fallback}
+			got := categoryShares(tt.tags, time.Hour, opts)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("categoryShares(%q) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
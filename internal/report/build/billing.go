@@ -0,0 +1,143 @@
+package build
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/billing"
+	"github.com/amiraminb/lume/internal/report/model"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// BillingReport prices the billable sessions between start and end at their
+// project or client rate, grouped by client, project and task. Each task is
+// priced to the cent, so every subtotal adds up to what is shown.
+func BillingReport(entries []timewarrior.Entry, start, end time.Time, opts Options) model.BillingReport {
+	type taskKey struct{ client, project, description string }
+	type taskTotals struct {
+		model.BilledTask
+		invoiced time.Duration
+		sessions map[time.Time]bool
+	}
+
+	report := model.BillingReport{Start: start, End: end}
+	if opts.Billing != nil {
+		report.Currency = opts.Billing.Currency
+	}
+
+	tasks := make(map[taskKey]*taskTotals)
+	for _, s := range spansOf(entries, start, end, opts) {
+		d := s.Duration()
+		if d <= 0 {
+			continue
+		}
+		if !opts.Billing.Billable(s.Entry) {
+			report.NonBillable += d
+			continue
+		}
+
+		key := taskKey{
+			client:      billing.Client(s.Entry),
+			project:     projectName(s.Entry),
+			description: s.Description,
+		}
+		if key.client == "" {
+			key.client = "unknown"
+		}
+		if key.description == "" {
+			key.description = "(no description)"
+		}
+		t := tasks[key]
+		if t == nil {
+			t = &taskTotals{sessions: make(map[time.Time]bool)}
+			t.Description = key.description
+			tasks[key] = t
+		}
+		t.Tracked += d
+		t.Billed += s.billed()
+		if billing.Invoiced(s.Entry) {
+			t.invoiced += s.billed()
+		}
+		t.sessions[s.origin] = true
+	}
+
+	clients := make(map[string]*model.ClientBilling)
+	projects := make(map[[2]string]*model.ProjectBilling)
+	for key, t := range tasks {
+		rate := opts.Billing.RateFor(key.client, key.project)
+		t.Sessions = len(t.sessions)
+		t.Amount = price(t.Billed, rate)
+		invoiced := price(t.invoiced, rate)
+
+		c := clients[key.client]
+		if c == nil {
+			c = &model.ClientBilling{Name: key.client}
+			clients[key.client] = c
+		}
+		p := projects[[2]string{key.client, key.project}]
+		if p == nil {
+			p = &model.ProjectBilling{Name: key.project, Rate: rate}
+			projects[[2]string{key.client, key.project}] = p
+		}
+		p.Tasks = append(p.Tasks, t.BilledTask)
+		p.Tracked += t.Tracked
+		p.Billed += t.Billed
+		p.Amount += t.Amount
+
+		report.Invoiced.Billed += t.invoiced
+		report.Invoiced.Amount += invoiced
+		report.Uninvoiced.Billed += t.Billed - t.invoiced
+		report.Uninvoiced.Amount += t.Amount - invoiced
+	}
+
+	for key, p := range projects {
+		sort.Slice(p.Tasks, func(i, j int) bool {
+			if p.Tasks[i].Billed != p.Tasks[j].Billed {
+				return p.Tasks[i].Billed > p.Tasks[j].Billed
+			}
+			return p.Tasks[i].Description < p.Tasks[j].Description
+		})
+		c := clients[key[0]]
+		c.Projects = append(c.Projects, *p)
+		c.Tracked += p.Tracked
+		c.Billed += p.Billed
+		c.Amount += p.Amount
+	}
+
+	for _, c := range clients {
+		sort.Slice(c.Projects, func(i, j int) bool {
+			if c.Projects[i].Amount != c.Projects[j].Amount {
+				return c.Projects[i].Amount > c.Projects[j].Amount
+			}
+			return c.Projects[i].Name < c.Projects[j].Name
+		})
+		report.Clients = append(report.Clients, *c)
+		report.Tracked += c.Tracked
+		report.Billed += c.Billed
+		report.Subtotal += c.Amount
+	}
+	sort.Slice(report.Clients, func(i, j int) bool {
+		if report.Clients[i].Amount != report.Clients[j].Amount {
+			return report.Clients[i].Amount > report.Clients[j].Amount
+		}
+		return report.Clients[i].Name < report.Clients[j].Name
+	})
+
+	report.Total = report.Subtotal
+	if opts.Billing != nil {
+		for _, tax := range opts.Billing.Taxes {
+			amount := int64(math.Round(float64(report.Subtotal) * tax.Percent / 100))
+			report.Taxes = append(report.Taxes, model.TaxLine{Name: tax.Name, Percent: tax.Percent, Amount: amount})
+			report.Total += amount
+		}
+	}
+	return report
+}
+
+// price is the amount in cents for billed time at rate cents per hour,
+// rounded to the nearest cent.
+func price(billed time.Duration, rate int64) int64 {
+	seconds := int64(billed.Round(time.Second) / time.Second)
+	return (seconds*rate + 1800) / 3600
+}
@@ -0,0 +1,27 @@
+package build
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrice(t *testing.T) {
+	tests := []struct {
+		billed time.Duration
+		rate   int64
+		want   int64
+	}{
+		{time.Hour, 9000, 9000},
+		{15 * time.Minute, 9000, 2250},
+		{20 * time.Minute, 10, 3},          // 3.33 cents
+		{30 * time.Minute, 1, 1},           // half a cent rounds up
+		{time.Second, 123457, 34},          // 34.29 cents
+		{1000 * time.Hour, 1000000, 1e9},   // no overflow at large rates
+		{1500 * time.Millisecond, 3600, 2}, // billed time rounds to the second
+	}
+	for _, tt := range tests {
+		if got := price(tt.billed, tt.rate); got != tt.want {
+			t.Errorf("price(%v, %d) = %d, want %d", tt.billed, tt.rate, got, tt.want)
+		}
+	}
+}
@@ -1,23 +1,49 @@
 package build
 
 import (
+	"slices"
 	"sort"
-	"strings"
 	"time"
 
+	"github.com/amiraminb/lume/internal/report/billing"
+	"github.com/amiraminb/lume/internal/report/civil"
 	"github.com/amiraminb/lume/internal/report/model"
+	"github.com/amiraminb/lume/internal/report/rules"
 	"github.com/amiraminb/lume/internal/timewarrior"
 )
 
-func YearReport(entries []timewarrior.Entry, year int) model.YearReport {
-	filtered := filterByYear(entries, year)
-	return yearReportFromEntries(filtered, year)
+// Options holds the user settings that change how entries are bucketed.
+type Options struct {
+	WeekStart time.Weekday
+	// ISOWeeks sets WeekData.WeekNum to the ISO 8601 week number instead of
+	// counting weeks from January 1.
+	ISOWeeks bool
+	// Rules assign categories, projects and tags to entries before they are
+	// aggregated. Nil applies none.
+	Rules *rules.Set
+	// Allocation shares out entries with several category tags; the zero
+	// value behaves like AllocateFull.
+	Allocation Allocation
+	// Categories and FallbackCategory define the task tables that
+	// TaskSummary.Categories is broken down by (see categoryShares). The
+	// fallback is also listed in Categories.
+	Categories       []string
+	FallbackCategory string
+	// Billing rounds each session into its billed time. Nil bills the
+	// tracked time.
+	Billing *billing.Policy
 }
 
-func YearReports(entries []timewarrior.Entry) []model.YearReport {
-	byYear := make(map[int][]timewarrior.Entry)
-	for _, entry := range entries {
-		byYear[entry.Start.Year()] = append(byYear[entry.Start.Year()], entry)
+func YearReport(entries []timewarrior.Entry, year int, opts Options) model.YearReport {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	spans := spansOf(entries, start, start.AddDate(1, 0, 0), opts)
+	return yearReportFromSpans(spans, year, opts)
+}
+
+func YearReports(entries []timewarrior.Entry, opts Options) []model.YearReport {
+	byYear := make(map[int][]span)
+	for _, s := range spansOf(entries, time.Time{}, time.Time{}, opts) {
+		byYear[s.Start.Year()] = append(byYear[s.Start.Year()], s)
 	}
 
 	var years []int
@@ -28,35 +54,38 @@ func YearReports(entries []timewarrior.Entry) []model.YearReport {
 
 	var reports []model.YearReport
 	for _, year := range years {
-		reports = append(reports, yearReportFromEntries(byYear[year], year))
+		reports = append(reports, yearReportFromSpans(byYear[year], year, opts))
 	}
 
 	return reports
 }
 
-func yearReportFromEntries(entries []timewarrior.Entry, year int) model.YearReport {
-	byMonth := groupByMonth(entries)
+func yearReportFromSpans(spans []span, year int, opts Options) model.YearReport {
+	byMonth := groupByMonth(spans)
 
 	var months []model.MonthData
-	var yearTotal float64
+	var yearTotal, yearBilled time.Duration
 
 	for month := time.January; month <= time.December; month++ {
-		monthEntries := byMonth[month]
-		if len(monthEntries) == 0 {
+		monthSpans := byMonth[month]
+		if len(monthSpans) == 0 {
 			continue
 		}
 
-		weeks := groupByWeek(monthEntries)
-		var monthTotal float64
+		weeks := groupByWeek(monthSpans, opts)
+		var monthTotal, monthBilled time.Duration
 		for _, w := range weeks {
 			monthTotal += w.Total
+			monthBilled += w.Billed
 		}
 		yearTotal += monthTotal
+		yearBilled += monthBilled
 
 		months = append(months, model.MonthData{
-			Month: month,
-			Weeks: weeks,
-			Total: monthTotal,
+			Month:  month,
+			Weeks:  weeks,
+			Total:  monthTotal,
+			Billed: monthBilled,
 		})
 	}
 
@@ -64,157 +93,209 @@ func yearReportFromEntries(entries []timewarrior.Entry, year int) model.YearRepo
 		Year:   year,
 		Months: months,
 		Total:  yearTotal,
+		Billed: yearBilled,
 	}
 }
 
-func WeekReport(entries []timewarrior.Entry, date time.Time) model.WeekData {
-	start := weekStart(date)
+// WeekReport reports the part of the week holding from that falls in
+// [from, to); the week is still labelled by its full bounds.
+func WeekReport(entries []timewarrior.Entry, from, to time.Time, opts Options) model.WeekData {
+	start := weekStart(from, opts.WeekStart)
 	end := start.AddDate(0, 0, 7)
-
-	var weekEntries []timewarrior.Entry
-	for _, e := range entries {
-		if !e.Start.Before(start) && e.Start.Before(end) {
-			weekEntries = append(weekEntries, e)
-		}
+	if to.Before(end) {
+		end = to
 	}
+	weekSpans := spansOf(entries, from, end, opts)
 
-	tasks := aggregateByDescription(weekEntries)
-	byTag := aggregateByTag(weekEntries)
-	byProject := aggregateByProject(weekEntries)
+	tasks := aggregateByDescription(weekSpans, opts)
+	byTag := aggregateByTag(weekSpans, opts)
+	byProject := aggregateByProject(weekSpans)
+	byDimension := aggregateByDimension(weekSpans)
 	weekStartDate, weekEndDate := weekBounds(start)
 
-	var total float64
-	for _, e := range weekEntries {
-		total += e.Duration().Hours()
+	var total, billed time.Duration
+	for _, s := range weekSpans {
+		total += s.Duration()
+		billed += s.billed()
 	}
 
 	return model.WeekData{
-		WeekNum:   weekNumber(start),
-		Start:     weekStartDate,
-		End:       weekEndDate,
-		Tasks:     tasks,
-		ByTag:     byTag,
-		ByProject: byProject,
-		Total:     total,
+		WeekNum:     weekNumber(start, opts),
+		Start:       weekStartDate,
+		End:         weekEndDate,
+		Tasks:       tasks,
+		ByTag:       byTag,
+		ByProject:   byProject,
+		ByDimension: byDimension,
+		Total:       total,
+		Billed:      billed,
 	}
 }
 
-func MonthReport(entries []timewarrior.Entry, month time.Month, year int) model.MonthData {
-	var monthEntries []timewarrior.Entry
-	for _, e := range entries {
-		if e.Start.Year() == year && e.Start.Month() == month {
-			monthEntries = append(monthEntries, e)
-		}
-	}
-
-	weeks := groupByWeek(monthEntries)
-	var total float64
+func MonthReport(entries []timewarrior.Entry, month time.Month, year int, opts Options) model.MonthData {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	weeks := groupByWeek(spansOf(entries, start, start.AddDate(0, 1, 0), opts), opts)
+	var total, billed time.Duration
 	for _, w := range weeks {
 		total += w.Total
+		billed += w.Billed
 	}
 
 	return model.MonthData{
-		Month: month,
-		Weeks: weeks,
-		Total: total,
+		Month:  month,
+		Weeks:  weeks,
+		Total:  total,
+		Billed: billed,
 	}
 }
 
-func DayReport(entries []timewarrior.Entry, date time.Time) model.DayReport {
+func DayReport(entries []timewarrior.Entry, date time.Time, opts Options) model.DayReport {
 	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	end := start.AddDate(0, 0, 1)
-
-	var dayEntries []timewarrior.Entry
-	for _, e := range entries {
-		if !e.Start.Before(start) && e.Start.Before(end) {
-			dayEntries = append(dayEntries, e)
-		}
-	}
-
-	tasks := aggregateByDescription(dayEntries)
-	byTag := aggregateByTag(dayEntries)
-	byProject := aggregateByProject(dayEntries)
-	var total float64
-	for _, e := range dayEntries {
-		total += e.Duration().Hours()
+	daySpans := spansOf(entries, start, start.AddDate(0, 0, 1), opts)
+
+	tasks := aggregateByDescription(daySpans, opts)
+	byTag := aggregateByTag(daySpans, opts)
+	byProject := aggregateByProject(daySpans)
+	byDimension := aggregateByDimension(daySpans)
+	var total, billed time.Duration
+	for _, s := range daySpans {
+		total += s.Duration()
+		billed += s.billed()
 	}
 
 	return model.DayReport{
-		Date:      start,
-		Tasks:     tasks,
-		ByTag:     byTag,
-		ByProject: byProject,
-		Total:     total,
+		Date:        start,
+		Tasks:       tasks,
+		ByTag:       byTag,
+		ByProject:   byProject,
+		ByDimension: byDimension,
+		Total:       total,
+		Billed:      billed,
 	}
 }
 
-func RangeReport(entries []timewarrior.Entry, start time.Time, end time.Time) model.MonthData {
-	var rangeEntries []timewarrior.Entry
-	for _, e := range entries {
-		if !e.Start.Before(start) && e.Start.Before(end) {
-			rangeEntries = append(rangeEntries, e)
-		}
-	}
-
-	weeks := groupByWeek(rangeEntries)
-	var total float64
+func RangeReport(entries []timewarrior.Entry, start time.Time, end time.Time, opts Options) model.MonthData {
+	weeks := groupByWeek(spansOf(entries, start, end, opts), opts)
+	var total, billed time.Duration
 	for _, w := range weeks {
 		total += w.Total
+		billed += w.Billed
 	}
 
 	return model.MonthData{
-		Weeks: weeks,
-		Total: total,
+		Weeks:  weeks,
+		Total:  total,
+		Billed: billed,
 	}
 }
 
-func filterByYear(entries []timewarrior.Entry, year int) []timewarrior.Entry {
-	var filtered []timewarrior.Entry
+// span is the part of an entry that falls within a single calendar day, and
+// within the report's bounds. Every bucket (day, week, month, year) is a
+// whole number of days, so a span always lands in exactly one of them.
+type span struct {
+	timewarrior.Entry
+	origin time.Time // start of the unsplit entry, so its pieces count as one session
+	// billedRatio is the entry's billed time over its tracked time; each
+	// piece of the entry bills its share of the session's rounding.
+	billedRatio float64
+}
+
+// Duration is the span's length to the second, the precision timewarrior
+// records; only the "now" end of an open interval carries anything finer.
+func (s span) Duration() time.Duration {
+	return s.Entry.Duration().Truncate(time.Second)
+}
+
+// billed is the span's share of its session's billed time.
+func (s span) billed() time.Duration {
+	return time.Duration(float64(s.Duration()) * s.billedRatio)
+}
+
+// spansOf applies the configured rules to entries, bills each billable one
+// as a whole session and splits them into day spans within [start, end).
+func spansOf(entries []timewarrior.Entry, start, end time.Time, opts Options) []span {
+	var spans []span
 	for _, e := range entries {
-		if e.Start.Year() == year {
-			filtered = append(filtered, e)
+		e = opts.Rules.Apply(e)
+		ratio := 1.0
+		if !opts.Billing.Billable(e) {
+			ratio = 0
+		} else if tracked := e.Duration().Truncate(time.Second); tracked > 0 {
+			ratio = float64(opts.Billing.Bill(e)) / float64(tracked)
 		}
+		spans = append(spans, splitEntry(e, start, end, ratio)...)
 	}
-	return filtered
+	return spans
 }
 
-func groupByMonth(entries []timewarrior.Entry) map[time.Month][]timewarrior.Entry {
-	grouped := make(map[time.Month][]timewarrior.Entry)
-	for _, e := range entries {
-		month := e.Start.Month()
-		grouped[month] = append(grouped[month], e)
+// splitEntry cuts an entry at each local midnight and clips it to
+// [start, end), so time is attributed to the day it was actually spent. A
+// zero start or end leaves that side unbounded.
+func splitEntry(e timewarrior.Entry, start, end time.Time, billedRatio float64) []span {
+	var spans []span
+	from, to := e.Start, e.End
+	if !start.IsZero() && from.Before(start) {
+		from = start
+	}
+	if !end.IsZero() && to.After(end) {
+		to = end
+	}
+
+	for from.Before(to) {
+		midnight := time.Date(from.Year(), from.Month(), from.Day()+1, 0, 0, 0, 0, from.Location())
+		pieceEnd := to
+		if midnight.Before(to) {
+			pieceEnd = midnight
+		}
+		piece := e
+		piece.Start, piece.End = from, pieceEnd
+		spans = append(spans, span{Entry: piece, origin: e.Start, billedRatio: billedRatio})
+		from = pieceEnd
+	}
+	return spans
+}
+
+func groupByMonth(spans []span) map[time.Month][]span {
+	grouped := make(map[time.Month][]span)
+	for _, s := range spans {
+		month := s.Start.Month()
+		grouped[month] = append(grouped[month], s)
 	}
 	return grouped
 }
 
-func groupByWeek(entries []timewarrior.Entry) []model.WeekData {
-	weekMap := make(map[time.Time][]timewarrior.Entry)
+func groupByWeek(spans []span, opts Options) []model.WeekData {
+	weekMap := make(map[time.Time][]span)
 
-	for _, e := range entries {
-		start := weekStart(e.Start)
-		weekMap[start] = append(weekMap[start], e)
+	for _, s := range spans {
+		start := weekStart(s.Start, opts.WeekStart)
+		weekMap[start] = append(weekMap[start], s)
 	}
 
 	var weeks []model.WeekData
-	for weekStartDate, weekEntries := range weekMap {
-		tasks := aggregateByDescription(weekEntries)
-		byTag := aggregateByTag(weekEntries)
-		byProject := aggregateByProject(weekEntries)
+	for weekStartDate, weekSpans := range weekMap {
+		tasks := aggregateByDescription(weekSpans, opts)
+		byTag := aggregateByTag(weekSpans, opts)
+		byProject := aggregateByProject(weekSpans)
+		byDimension := aggregateByDimension(weekSpans)
 		start, end := weekBounds(weekStartDate)
 
-		var total float64
-		for _, e := range weekEntries {
-			total += e.Duration().Hours()
+		var total, billed time.Duration
+		for _, s := range weekSpans {
+			total += s.Duration()
+			billed += s.billed()
 		}
 
 		weeks = append(weeks, model.WeekData{
-			WeekNum:   weekNumber(weekStartDate),
-			Start:     start,
-			End:       end,
-			Tasks:     tasks,
-			ByTag:     byTag,
-			ByProject: byProject,
-			Total:     total,
+			WeekNum:     weekNumber(weekStartDate, opts),
+			Start:       start,
+			End:         end,
+			Tasks:       tasks,
+			ByTag:       byTag,
+			ByProject:   byProject,
+			ByDimension: byDimension,
+			Total:       total,
+			Billed:      billed,
 		})
 	}
 
@@ -225,15 +306,17 @@ func groupByWeek(entries []timewarrior.Entry) []model.WeekData {
 	return weeks
 }
 
-func aggregateByDescription(entries []timewarrior.Entry) []model.TaskSummary {
+func aggregateByDescription(spans []span, opts Options) []model.TaskSummary {
 	taskMap := make(map[string]*model.TaskSummary)
+	sessions := make(map[string]map[time.Time]bool)
+	categorySessions := make(map[string]map[string]map[time.Time]bool)
 
-	for _, e := range entries {
+	for _, e := range spans {
 		desc := e.Description
 		if desc == "" {
 			desc = "(no description)"
 		}
-		project := projectFromTags(e.Tags)
+		project := projectName(e.Entry)
 		key := project + "\x00" + desc
 
 		if _, exists := taskMap[key]; !exists {
@@ -241,16 +324,52 @@ func aggregateByDescription(entries []timewarrior.Entry) []model.TaskSummary {
 				Description: desc,
 				Project:     project,
 				Tags:        make(map[string]bool),
-				DayTotals:   make(map[time.Weekday]float64),
+				DayTotals:   make(map[time.Weekday]time.Duration),
+				Categories:  make(map[string]model.CategoryTime),
+				Dimensions:  make(map[string]string),
 			}
+			sessions[key] = make(map[time.Time]bool)
+			categorySessions[key] = make(map[string]map[time.Time]bool)
+		}
+		taskMap[key].TotalTime += e.Duration()
+		taskMap[key].BilledTime += e.billed()
+		if !sessions[key][e.origin] {
+			sessions[key][e.origin] = true
+			taskMap[key].Sessions++
 		}
-		taskMap[key].TotalTime += e.Duration().Hours()
-		taskMap[key].Sessions++
 		weekday := e.Start.Weekday()
-		taskMap[key].DayTotals[weekday] += e.Duration().Hours()
+		taskMap[key].DayTotals[weekday] += e.Duration()
 		for _, tag := range e.Tags {
 			taskMap[key].Tags[tag] = true
 		}
+		for dimension, value := range dimensionsFromTags(e.Tags) {
+			if _, ok := taskMap[key].Dimensions[dimension]; !ok {
+				taskMap[key].Dimensions[dimension] = value
+			}
+		}
+		for category, d := range categoryShares(e.Tags, e.Duration(), opts) {
+			c := taskMap[key].Categories[category]
+			if c.DayTotals == nil {
+				c.DayTotals = make(map[time.Weekday]time.Duration)
+				categorySessions[key][category] = make(map[time.Time]bool)
+			}
+			c.Total += d
+			switch {
+			case d == e.Duration():
+				c.Billed += e.billed()
+			case e.Duration() > 0:
+				c.Billed += time.Duration(float64(e.billed()) * float64(d) / float64(e.Duration()))
+			}
+			c.DayTotals[weekday] += d
+			if !categorySessions[key][category][e.origin] {
+				categorySessions[key][category][e.origin] = true
+				c.Sessions++
+			}
+			taskMap[key].Categories[category] = c
+		}
+		if e.Annotation != "" && !slices.Contains(taskMap[key].Annotations, e.Annotation) {
+			taskMap[key].Annotations = append(taskMap[key].Annotations, e.Annotation)
+		}
 	}
 
 	var tasks []model.TaskSummary
@@ -265,58 +384,101 @@ func aggregateByDescription(entries []timewarrior.Entry) []model.TaskSummary {
 	return tasks
 }
 
-func aggregateByProject(entries []timewarrior.Entry) map[string]float64 {
-	projectTime := make(map[string]float64)
-	for _, e := range entries {
-		project := projectFromTags(e.Tags)
-		projectTime[project] += e.Duration().Hours()
+func aggregateByProject(spans []span) map[string]time.Duration {
+	projectTime := make(map[string]time.Duration)
+	for _, e := range spans {
+		project := projectName(e.Entry)
+		projectTime[project] += e.Duration()
 	}
 	return projectTime
 }
 
-func aggregateByTag(entries []timewarrior.Entry) map[string]float64 {
-	tagTime := make(map[string]float64)
-	for _, e := range entries {
-		hasNonProjectTag := false
-		for _, tag := range e.Tags {
-			if strings.HasPrefix(tag, "project:") {
-				continue
+func aggregateByTag(spans []span, opts Options) map[string]time.Duration {
+	tagTime := make(map[string]time.Duration)
+	for _, e := range spans {
+		for tag, d := range allocate(categoryTags(e.Tags), e.Duration(), opts) {
+			tagTime[tag] += d
+		}
+	}
+	return tagTime
+}
+
+// aggregateByDimension sums the time per value of every dimension found in
+// spans. Spans without a value for a dimension count towards "unknown", so
+// each dimension's values add up to the total, like ByProject.
+func aggregateByDimension(spans []span) map[string]map[string]time.Duration {
+	byDimension := make(map[string]map[string]time.Duration)
+	for _, e := range spans {
+		for dimension := range dimensionsFromTags(e.Tags) {
+			if byDimension[dimension] == nil {
+				byDimension[dimension] = make(map[string]time.Duration)
 			}
-			hasNonProjectTag = true
-			tagTime[tag] += e.Duration().Hours()
 		}
-		if !hasNonProjectTag {
-			tagTime["untagged"] += e.Duration().Hours()
+	}
+	for _, e := range spans {
+		values := dimensionsFromTags(e.Tags)
+		for dimension, totals := range byDimension {
+			value, ok := values[dimension]
+			if !ok {
+				value = "unknown"
+			}
+			totals[value] += e.Duration()
 		}
 	}
-	return tagTime
+	return byDimension
 }
 
-func projectFromTags(tags []string) string {
+// dimensionsFromTags returns the entry's key:value tags other than project,
+// by key. When a key repeats, the first value wins, as for projects.
+func dimensionsFromTags(tags []string) map[string]string {
+	dimensions := make(map[string]string)
 	for _, tag := range tags {
-		if strings.HasPrefix(tag, "project:") {
-			name := strings.TrimPrefix(tag, "project:")
-			if name != "" {
-				return name
-			}
+		key, value, ok := model.ParseDimension(tag)
+		if !ok || key == "project" {
+			continue
 		}
+		if _, seen := dimensions[key]; !seen {
+			dimensions[key] = value
+		}
+	}
+	return dimensions
+}
+
+// projectName is the entry's project, or "unknown" when it has none.
+func projectName(e timewarrior.Entry) string {
+	if project := e.Project(); project != "" {
+		return project
 	}
 	return "unknown"
 }
 
-func weekStart(t time.Time) time.Time {
-	weekday := int(t.Weekday())
+// InOneWeek reports whether [start, end) lies within a single week.
+func InOneWeek(start, end time.Time, opts Options) bool {
+	return !end.After(weekStart(start, opts.WeekStart).AddDate(0, 0, 7))
+}
+
+func weekStart(t time.Time, first time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(first) + 7) % 7
 	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
-	return start.AddDate(0, 0, -weekday)
+	return start.AddDate(0, 0, -offset)
 }
 
 func weekBounds(start time.Time) (time.Time, time.Time) {
-	end := start.AddDate(0, 0, 6).Add(time.Hour*23 + time.Minute*59 + time.Second*59)
+	end := time.Date(start.Year(), start.Month(), start.Day()+6, 23, 59, 59, 0, start.Location())
 	return start, end
 }
 
-func weekNumber(start time.Time) int {
-	startOfYear := weekStart(time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, start.Location()))
-	weeks := int(start.Sub(startOfYear).Hours() / 24 / 7)
+// weekNumber numbers the week beginning at start. ISO numbering takes the
+// Thursday within the week, so weeks that start on another day than Monday
+// are numbered after the ISO week holding most of their days.
+func weekNumber(start time.Time, opts Options) int {
+	if opts.ISOWeeks {
+		day := civil.DateOf(start)
+		thursday := day.AddDays((int(time.Thursday) - int(day.Weekday()) + 7) % 7)
+		_, week := thursday.In(time.UTC).ISOWeek()
+		return week
+	}
+	startOfYear := weekStart(time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, start.Location()), opts.WeekStart)
+	weeks := civil.DateOf(start).DaysSince(civil.DateOf(startOfYear)) / 7
 	return weeks + 1
 }
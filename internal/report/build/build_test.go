@@ -0,0 +1,47 @@
+package build
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestAggregateByDimension(t *testing.T) {
+	start := time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+	spanOf := func(d time.Duration, tags ...string) span {
+		return span{Entry: timewarrior.Entry{Start: start, End: start.Add(d), Tags: tags}}
+	}
+	spans := []span{
+		spanOf(time.Hour, "dev", "client:acme", "env:prod", "project:acme.api"),
+		spanOf(30*time.Minute, "client:globex", "client:acme"),
+		spanOf(15*time.Minute, "https://example.com/issue/1", "10:30"),
+	}
+
+	got := aggregateByDimension(spans)
+	want := map[string]map[string]time.Duration{
+		"client": {"acme": time.Hour, "globex": 30 * time.Minute, "unknown": 15 * time.Minute},
+		"env":    {"prod": time.Hour, "unknown": 45 * time.Minute},
+	}
+	if !maps.EqualFunc(got, want, maps.Equal) {
+		t.Errorf("aggregateByDimension() = %v, want %v", got, want)
+	}
+}
+
+func TestCategoryTags(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"dev", "project:acme", "client:acme", "billable"}, []string{"dev"}},
+		{[]string{"https://example.com", "10:30"}, []string{"https://example.com", "10:30"}},
+		{[]string{"project:acme", "invoiced"}, []string{"untagged"}},
+	}
+	for _, tt := range tests {
+		if got := categoryTags(tt.tags); !slices.Equal(got, tt.want) {
+			t.Errorf("categoryTags(%q) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
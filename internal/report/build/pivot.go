@@ -0,0 +1,157 @@
+package build
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// Built-in pivot dimensions. Any other name is read as the key of a
+// key:value tag, such as client for client:acme.
+const (
+	PivotProject     = "project"
+	PivotTag         = "tag"
+	PivotDescription = "description"
+	PivotWeekday     = "weekday"
+	PivotDay         = "day"
+	PivotWeek        = "week"
+	PivotMonth       = "month"
+)
+
+// PivotDimensions lists the dimensions entries can be pivoted by: the
+// built-in ones, then the keys of the key:value tags they carry once
+// opts.Rules are applied, in sorted order.
+func PivotDimensions(entries []timewarrior.Entry, opts Options) []string {
+	dimensions := []string{PivotProject, PivotTag, PivotDescription, PivotWeekday, PivotDay, PivotWeek, PivotMonth}
+	var keys []string
+	for _, e := range entries {
+		for key := range dimensionsFromTags(opts.Rules.Apply(e).Tags) {
+			if !slices.Contains(dimensions, key) && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return append(dimensions, keys...)
+}
+
+// PivotReport cross-tabulates the entries between start and end by two
+// dimensions. Time axes (day, week, month, weekday) are ordered by time, all
+// others by time descending.
+func PivotReport(entries []timewarrior.Entry, start, end time.Time, rowBy, columnBy string, opts Options) model.Pivot {
+	cells := make(map[string]map[string]time.Duration)
+	rowTotals := make(map[string]time.Duration)
+	columnTotals := make(map[string]time.Duration)
+	var total time.Duration
+
+	for _, s := range spansOf(entries, start, end, opts) {
+		d := s.Duration()
+		if d <= 0 {
+			continue
+		}
+		total += d
+		rows := pivotShares(s, rowBy, opts)
+		columns := pivotShares(s, columnBy, opts)
+		for row, rowTime := range rows {
+			rowTotals[row] += rowTime
+			if cells[row] == nil {
+				cells[row] = make(map[string]time.Duration)
+			}
+			for column, columnTime := range columns {
+				// Multiplying two durations overflows int64 nanoseconds.
+				cells[row][column] += time.Duration(float64(rowTime) * float64(columnTime) / float64(d))
+			}
+		}
+		for column, columnTime := range columns {
+			columnTotals[column] += columnTime
+		}
+	}
+
+	pivot := model.Pivot{
+		RowDimension:    rowBy,
+		ColumnDimension: columnBy,
+		Rows:            pivotOrder(rowTotals, rowBy, opts),
+		Columns:         pivotOrder(columnTotals, columnBy, opts),
+		Total:           total,
+	}
+	for _, row := range pivot.Rows {
+		line := make([]time.Duration, len(pivot.Columns))
+		for i, column := range pivot.Columns {
+			line[i] = cells[row][column]
+		}
+		pivot.Cells = append(pivot.Cells, line)
+		pivot.RowTotals = append(pivot.RowTotals, rowTotals[row])
+	}
+	for _, column := range pivot.Columns {
+		pivot.ColumnTotals = append(pivot.ColumnTotals, columnTotals[column])
+	}
+	return pivot
+}
+
+// pivotShares returns the labels a span falls under along one dimension,
+// with the time each receives. Only tags can yield several labels, shared
+// out by opts.Allocation.
+func pivotShares(s span, by string, opts Options) map[string]time.Duration {
+	d := s.Duration()
+	var label string
+	switch by {
+	case PivotProject:
+		label = projectName(s.Entry)
+	case PivotTag:
+		return allocate(categoryTags(s.Tags), d, opts)
+	case PivotDescription:
+		label = s.Description
+		if label == "" {
+			label = "(no description)"
+		}
+	case PivotWeekday:
+		label = s.Start.Weekday().String()
+	case PivotDay:
+		label = s.Start.Format("2006-01-02")
+	case PivotWeek:
+		label = weekStart(s.Start, opts.WeekStart).Format("2006-01-02")
+	case PivotMonth:
+		label = s.Start.Format("2006-01")
+	default:
+		value, ok := dimensionsFromTags(s.Tags)[by]
+		if !ok {
+			value = "unknown"
+		}
+		label = value
+	}
+	return map[string]time.Duration{label: d}
+}
+
+// pivotOrder sorts the labels of one pivot axis.
+func pivotOrder(totals map[string]time.Duration, by string, opts Options) []string {
+	labels := make([]string, 0, len(totals))
+	if by == PivotWeekday {
+		for i := range 7 {
+			day := ((opts.WeekStart + time.Weekday(i)) % 7).String()
+			if _, ok := totals[day]; ok {
+				labels = append(labels, day)
+			}
+		}
+		return labels
+	}
+
+	for label := range totals {
+		labels = append(labels, label)
+	}
+	switch by {
+	case PivotDay, PivotWeek, PivotMonth:
+		// ISO dates sort chronologically as strings.
+		sort.Strings(labels)
+	default:
+		sort.Slice(labels, func(i, j int) bool {
+			if totals[labels[i]] != totals[labels[j]] {
+				return totals[labels[i]] > totals[labels[j]]
+			}
+			return labels[i] < labels[j]
+		})
+	}
+	return labels
+}
@@ -0,0 +1,20 @@
+package build
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestPivotDimensions(t *testing.T) {
+	entries := []timewarrior.Entry{
+		{Tags: []string{"dev", "project:acme", "env:prod"}},
+		{Tags: []string{"client:acme", "env:dev", "note:"}},
+	}
+	got := PivotDimensions(entries, Options{})
+	want := []string{"project", "tag", "description", "weekday", "day", "week", "month", "client", "env"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PivotDimensions() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"sort"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// Sessions lists entries one by one in start order, with rules applied and
+// each session billed as a whole. Unlike the reports, sessions are neither
+// split at midnight nor clipped to the report range.
+func Sessions(entries []timewarrior.Entry, opts Options) []model.Session {
+	sessions := make([]model.Session, 0, len(entries))
+	for _, e := range entries {
+		e = opts.Rules.Apply(e)
+		tracked := e.Duration().Truncate(time.Second)
+		billed := opts.Billing.Bill(e)
+		if !opts.Billing.Billable(e) {
+			billed = 0
+		}
+		sessions = append(sessions, model.Session{
+			Start:       e.Start,
+			End:         e.End,
+			Project:     projectName(e),
+			Description: e.Description,
+			Tags:        e.Tags,
+			Annotation:  e.Annotation,
+			Tracked:     tracked,
+			Billed:      billed,
+		})
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions
+}
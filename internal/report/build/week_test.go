@@ -0,0 +1,114 @@
+package build
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestWeekNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		opts  Options
+		want  int
+	}{
+		{"iso monday", "2025-01-06", Options{ISOWeeks: true, WeekStart: time.Monday}, 2},
+		{"iso tuesday start takes its thursday", "2025-01-07", Options{ISOWeeks: true, WeekStart: time.Tuesday}, 2},
+		{"iso friday start takes the next week", "2025-01-03", Options{ISOWeeks: true, WeekStart: time.Friday}, 2},
+		{"iso sunday start", "2024-12-29", Options{ISOWeeks: true, WeekStart: time.Sunday}, 1},
+		{"iso week 53", "2020-12-28", Options{ISOWeeks: true, WeekStart: time.Monday}, 53},
+		{"from january 1", "2025-01-05", Options{WeekStart: time.Sunday}, 2},
+		{"counted in the year it starts", "2024-12-29", Options{WeekStart: time.Sunday}, 53},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := time.ParseInLocation(time.DateOnly, tt.start, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := weekNumber(start, tt.opts); got != tt.want {
+				t.Errorf("weekNumber(%s) = %d, want %d", tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekNumberAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone    string
+		start   string // local midnight starting a week with a clock change
+		first   time.Weekday
+		want    int // counted from January 1
+		wantISO int
+	}{
+		{"America/New_York", "2025-03-09", time.Sunday, 11, 11},
+		{"America/New_York", "2025-03-16", time.Sunday, 12, 12},
+		{"America/New_York", "2025-11-02", time.Sunday, 45, 45},
+		{"Europe/Berlin", "2025-03-24", time.Monday, 13, 13},
+		{"Europe/Berlin", "2025-10-20", time.Monday, 43, 43},
+		{"Australia/Sydney", "2025-04-06", time.Sunday, 15, 15},
+		{"Australia/Sydney", "2025-10-05", time.Sunday, 41, 41},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.start, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, _ := time.ParseInLocation(time.DateOnly, tt.start, loc)
+			if got := weekNumber(start, Options{WeekStart: tt.first}); got != tt.want {
+				t.Errorf("weekNumber = %d, want %d", got, tt.want)
+			}
+			if got := weekNumber(start, Options{WeekStart: tt.first, ISOWeeks: true}); got != tt.wantISO {
+				t.Errorf("ISO weekNumber = %d, want %d", got, tt.wantISO)
+			}
+			// The next week, 167 or 169 hours later, is numbered one higher.
+			next := weekStart(start.Add(7*24*time.Hour+2*time.Hour), tt.first)
+			if got := weekNumber(next, Options{WeekStart: tt.first}); got != tt.want+1 {
+				t.Errorf("next weekNumber = %d, want %d", got, tt.want+1)
+			}
+		})
+	}
+}
+
+func TestWeekReportClipsToRange(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, time.March, d, h, 0, 0, 0, time.UTC) }
+	entries := []timewarrior.Entry{
+		{Start: day(10, 9), End: day(10, 11), Description: "before"}, // Monday
+		{Start: day(11, 23), End: day(12, 1), Description: "across"},
+		{Start: day(13, 9), End: day(13, 10), Description: "inside"},
+		{Start: day(14, 9), End: day(14, 12), Description: "after"},
+	}
+	opts := Options{WeekStart: time.Monday}
+
+	week := WeekReport(entries, day(11, 0), day(14, 0), opts)
+	if want := 3 * time.Hour; week.Total != want {
+		t.Errorf("Total = %v, want %v", week.Total, want)
+	}
+	if want := day(10, 0); !week.Start.Equal(want) {
+		t.Errorf("Start = %v, want the week's start %v", week.Start, want)
+	}
+}
+
+func TestInOneWeek(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+	opts := Options{WeekStart: time.Monday}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"whole week", day(10), day(17), true},
+		{"tuesday to next monday", day(11), day(17), true},
+		{"thursday to next tuesday", day(13), day(18), false},
+		{"sunday to monday", day(16), day(18), false},
+	}
+	for _, tt := range tests {
+		if got := InOneWeek(tt.start, tt.end, opts); got != tt.want {
+			t.Errorf("%s: InOneWeek = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
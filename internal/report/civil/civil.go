@@ -0,0 +1,45 @@
+// Package civil does calendar arithmetic on dates without a time of day, so
+// differences are counted in whole days regardless of DST: a day that is 23
+// or 25 hours long still counts as one.
+package civil
+
+import "time"
+
+// Date is a calendar date in no particular time zone.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the calendar date of t in t's own location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// In returns midnight at the start of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns the date n days after d (before, for negative n).
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.utc().AddDate(0, 0, n))
+}
+
+// DaysSince returns the number of calendar days from o to d, negative when d
+// is earlier.
+func (d Date) DaysSince(o Date) int {
+	return int(d.utc().Sub(o.utc()).Hours() / 24)
+}
+
+// Weekday returns the day of the week d falls on.
+func (d Date) Weekday() time.Weekday {
+	return d.utc().Weekday()
+}
+
+// utc anchors d in UTC, where every day is exactly 24 hours long.
+func (d Date) utc() time.Time {
+	return d.In(time.UTC)
+}
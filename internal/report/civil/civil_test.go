@@ -0,0 +1,77 @@
+package civil
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// dstZones are zones whose clocks change in different months and
+// hemispheres.
+var dstZones = []string{"America/New_York", "Europe/Berlin", "Australia/Sydney"}
+
+func load(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestDaysSinceAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone     string
+		from, to string // local midnights around a clock change
+		want     int
+	}{
+		{"America/New_York", "2025-03-09", "2025-03-10", 1}, // 23 hours
+		{"America/New_York", "2025-03-08", "2025-03-15", 7},
+		{"America/New_York", "2025-11-02", "2025-11-03", 1}, // 25 hours
+		{"America/New_York", "2025-11-01", "2025-11-08", 7},
+		{"Europe/Berlin", "2025-03-30", "2025-03-31", 1},
+		{"Europe/Berlin", "2025-03-24", "2025-03-31", 7},
+		{"Europe/Berlin", "2025-10-26", "2025-10-27", 1},
+		{"Europe/Berlin", "2025-10-20", "2025-10-27", 7},
+		{"Australia/Sydney", "2025-04-06", "2025-04-07", 1},
+		{"Australia/Sydney", "2025-03-31", "2025-04-07", 7},
+		{"Australia/Sydney", "2025-10-05", "2025-10-06", 1},
+		{"Australia/Sydney", "2025-09-29", "2025-10-06", 7},
+		{"Australia/Sydney", "2025-10-06", "2025-09-29", -7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.from+" "+tt.to, func(t *testing.T) {
+			loc := load(t, tt.zone)
+			from, _ := time.ParseInLocation(time.DateOnly, tt.from, loc)
+			to, _ := time.ParseInLocation(time.DateOnly, tt.to, loc)
+			if got := DateOf(to).DaysSince(DateOf(from)); got != tt.want {
+				t.Errorf("DaysSince = %d, want %d (%v apart)", got, tt.want, to.Sub(from))
+			}
+		})
+	}
+}
+
+func TestAddDaysAndWeekday(t *testing.T) {
+	d := Date{2025, time.March, 9}
+	if got := d.AddDays(1); got != (Date{2025, time.March, 10}) {
+		t.Errorf("AddDays(1) = %v", got)
+	}
+	if got := d.AddDays(-9); got != (Date{2025, time.February, 28}) {
+		t.Errorf("AddDays(-9) = %v", got)
+	}
+	if got := d.Weekday(); got != time.Sunday {
+		t.Errorf("Weekday = %v, want Sunday", got)
+	}
+}
+
+func TestInIsLocalMidnight(t *testing.T) {
+	for _, zone := range dstZones {
+		loc := load(t, zone)
+		for _, d := range []Date{{2025, time.March, 9}, {2025, time.March, 30}, {2025, time.April, 6}, {2025, time.October, 5}, {2025, time.October, 26}, {2025, time.November, 2}} {
+			got := d.In(loc)
+			if h, m, _ := got.Clock(); h != 0 || m != 0 || DateOf(got) != d {
+				t.Errorf("%s: %v.In = %v", zone, d, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// BillingReport is the billable time and money of a range, grouped by
+// client, then project, then task. Amounts are in cents of Currency and
+// rates in cents per hour, so they add up exactly.
+type BillingReport struct {
+	Start    time.Time
+	End      time.Time
+	Currency string
+	Clients  []ClientBilling
+	Tracked  time.Duration
+	Billed   time.Duration
+	// Subtotal is the amount before taxes; Total includes every tax line.
+	Subtotal int64
+	Taxes    []TaxLine
+	Total    int64
+	// Invoiced is the part already billed (tagged invoiced), Uninvoiced the
+	// part still to bill; both are before taxes.
+	Invoiced   BillingSplit
+	Uninvoiced BillingSplit
+	// NonBillable is the tracked time left out of the report.
+	NonBillable time.Duration
+}
+
+type ClientBilling struct {
+	Name     string
+	Projects []ProjectBilling
+	Tracked  time.Duration
+	Billed   time.Duration
+	Amount   int64
+}
+
+type ProjectBilling struct {
+	Name    string
+	Rate    int64
+	Tasks   []BilledTask
+	Tracked time.Duration
+	Billed  time.Duration
+	Amount  int64
+}
+
+type BilledTask struct {
+	Description string
+	Sessions    int
+	Tracked     time.Duration
+	Billed      time.Duration
+	Amount      int64
+}
+
+type TaxLine struct {
+	Name    string
+	Percent float64
+	Amount  int64
+}
+
+type BillingSplit struct {
+	Billed time.Duration
+	Amount int64
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParseDimension splits a "key:value" tag such as "client:acme" or
+// "ticket:LUME-42" into its dimension key and value. The key must be a word:
+// a letter followed by letters, digits, '-' or '_'. Tags that merely contain
+// a colon, such as "10:30" or "https://example.com", stay plain tags, as do
+// tags with an empty value.
+func ParseDimension(tag string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(tag, ":")
+	if !ok || !isDimensionKey(key) || value == "" || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func isDimensionKey(key string) bool {
+	for i, r := range key {
+		switch {
+		case unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '_'):
+		default:
+			return false
+		}
+	}
+	return key != ""
+}
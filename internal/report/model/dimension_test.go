@@ -0,0 +1,34 @@
+package model
+
+import "testing"
+
+func TestParseDimension(t *testing.T) {
+	tests := []struct {
+		tag        string
+		key, value string
+		ok         bool
+	}{
+		{"client:acme", "client", "acme", true},
+		{"ticket:LUME-42", "ticket", "LUME-42", true},
+		{"project:acme.api", "project", "acme.api", true},
+		{"cost_center:7", "cost_center", "7", true},
+		{"env-2:prod", "env-2", "prod", true},
+		{"note:a:b", "note", "a:b", true},
+		{"Kunde:müller", "Kunde", "müller", true},
+		{"dev", "", "", false},
+		{"10:30", "", "", false},
+		{"https://example.com", "", "", false},
+		{"http://x", "", "", false},
+		{"2nd:try", "", "", false},
+		{"a b:c", "", "", false},
+		{"key.sub:x", "", "", false},
+		{":acme", "", "", false},
+		{"note:", "", "", false},
+	}
+	for _, tt := range tests {
+		key, value, ok := ParseDimension(tt.tag)
+		if key != tt.key || value != tt.value || ok != tt.ok {
+			t.Errorf("ParseDimension(%q) = %q, %q, %t, want %q, %q, %t", tt.tag, key, value, ok, tt.key, tt.value, tt.ok)
+		}
+	}
+}
@@ -5,10 +5,29 @@ import "time"
 type TaskSummary struct {
 	Description string
 	Project     string
-	TotalTime   float64
+	TotalTime   time.Duration
+	// BilledTime is TotalTime after billing rounds each session; it equals
+	// TotalTime when no billing policy is configured.
+	BilledTime  time.Duration
 	Sessions    int
 	Tags        map[string]bool
-	DayTotals   map[time.Weekday]float64
+	DayTotals   map[time.Weekday]time.Duration
+	Annotations []string
+	// Categories holds the task's part of each report category table, after
+	// allocating entries that carry several category tags.
+	Categories map[string]CategoryTime
+	// Dimensions maps each key:value tag key other than project (client,
+	// ticket, env, ...) to the task's value for it.
+	Dimensions map[string]string
+}
+
+// CategoryTime is the part of a task that falls in one category, summed
+// from the sessions that count there.
+type CategoryTime struct {
+	Total     time.Duration
+	Billed    time.Duration
+	Sessions  int
+	DayTotals map[time.Weekday]time.Duration
 }
 
 type WeekData struct {
@@ -16,27 +35,37 @@ type WeekData struct {
 	Start     time.Time
 	End       time.Time
 	Tasks     []TaskSummary
-	ByTag     map[string]float64
-	ByProject map[string]float64
-	Total     float64
+	ByTag     map[string]time.Duration
+	ByProject map[string]time.Duration
+	// ByDimension holds the time per value of every key:value dimension seen;
+	// time without a value for a dimension counts as "unknown".
+	ByDimension map[string]map[string]time.Duration
+	Total       time.Duration
+	Billed      time.Duration
 }
 
 type MonthData struct {
-	Month time.Month
-	Weeks []WeekData
-	Total float64
+	Month  time.Month
+	Weeks  []WeekData
+	Total  time.Duration
+	Billed time.Duration
 }
 
 type YearReport struct {
 	Year   int
 	Months []MonthData
-	Total  float64
+	Total  time.Duration
+	Billed time.Duration
 }
 
 type DayReport struct {
 	Date      time.Time
 	Tasks     []TaskSummary
-	ByTag     map[string]float64
-	ByProject map[string]float64
-	Total     float64
+	ByTag     map[string]time.Duration
+	ByProject map[string]time.Duration
+	// ByDimension holds the time per value of every key:value dimension seen;
+	// time without a value for a dimension counts as "unknown".
+	ByDimension map[string]map[string]time.Duration
+	Total       time.Duration
+	Billed      time.Duration
 }
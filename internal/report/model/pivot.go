@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Pivot is a matrix of tracked time with one user-chosen dimension on each axis,
+// e.g. clients by week. Rows and Columns are labels in display order.
+//
+// Totals are the time behind each row and column, not the sums of their
+// cells: under full allocation an entry tagged "dev review" counts whole in
+// both tag columns but only once in its row total.
+type Pivot struct {
+	RowDimension    string
+	ColumnDimension string
+	Rows            []string
+	Columns         []string
+	Cells           [][]time.Duration // indexed by row then column
+	RowTotals       []time.Duration
+	ColumnTotals    []time.Duration
+	Total           time.Duration
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProjectNode is one level of a hierarchical project name such as
+// "acme.api.auth" (client → product → component). Total includes every
+// project below the node.
+type ProjectNode struct {
+	Name     string // last segment, e.g. "auth"
+	Path     string // full dotted path, e.g. "acme.api.auth"
+	Total    time.Duration
+	Children []*ProjectNode
+}
+
+// ProjectTree rolls per-project time up into a tree, treating "." and "/"
+// in project names as level separators. Each level is sorted by time,
+// descending, then by name.
+func ProjectTree(byProject map[string]time.Duration) []*ProjectNode {
+	root := &ProjectNode{}
+	index := make(map[string]*ProjectNode)
+
+	for project, d := range byProject {
+		segments := strings.FieldsFunc(project, func(r rune) bool { return r == '.' || r == '/' })
+		if len(segments) == 0 {
+			segments = []string{project}
+		}
+
+		parent := root
+		for i, segment := range segments {
+			path := strings.Join(segments[:i+1], ".")
+			node, ok := index[path]
+			if !ok {
+				node = &ProjectNode{Name: segment, Path: path}
+				index[path] = node
+				parent.Children = append(parent.Children, node)
+			}
+			node.Total += d
+			parent = node
+		}
+	}
+
+	sortProjectNodes(root.Children)
+	return root.Children
+}
+
+func sortProjectNodes(nodes []*ProjectNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Total != nodes[j].Total {
+			return nodes[i].Total > nodes[j].Total
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, n := range nodes {
+		sortProjectNodes(n.Children)
+	}
+}
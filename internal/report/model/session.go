@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Session is one tracked interval, unaggregated, after rules have run.
+type Session struct {
+	Start       time.Time
+	End         time.Time
+	Project     string
+	Description string
+	Tags        []string
+	Annotation  string
+	Tracked     time.Duration
+	// Billed is Tracked after billing rounds the session; it equals Tracked
+	// when no billing policy is configured.
+	Billed time.Duration
+}
@@ -3,7 +3,6 @@ package render
 import (
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 	"time"
 
@@ -51,32 +50,38 @@ func renderColorBar(ratio float64, color lipgloss.Color) string {
 
 // writeColorShareChart renders a labelled breakdown as a bordered table sorted
 // by time descending, with each row's share of the total.
-func writeColorShareChart(file *os.File, title string, values map[string]float64, total float64) {
-	rows := make([]chartRow, 0, len(values))
-	for label, hours := range values {
-		rows = append(rows, chartRow{label: label, hours: hours})
+func writeColorShareChart(file *os.File, title string, values map[string]time.Duration, total time.Duration, opts Options) {
+	writeColorShareRows(file, title, sortedShareRows(values, total, opts), total)
+}
+
+// writeColorProjectChart renders the Projects table as an indented tree of
+// rolled-up project levels.
+func writeColorProjectChart(file *os.File, byProject map[string]time.Duration, total time.Duration, opts Options) {
+	writeColorShareRows(file, "Projects", projectRows(byProject, total, opts, "  "), total)
+}
+
+// writeColorDimensionCharts renders a share table for each configured
+// dimension that occurs in byDimension.
+func writeColorDimensionCharts(file *os.File, byDimension map[string]map[string]time.Duration, total time.Duration, opts Options) {
+	for _, dimension := range opts.Dimensions {
+		if values := byDimension[dimension]; len(values) > 0 {
+			writeColorShareChart(file, categoryTitle(dimension), values, total, opts)
+		}
 	}
+}
+
+// writeColorShareRows draws pre-ordered share rows; see writeColorShareChart.
+func writeColorShareRows(file *os.File, title string, rows []chartRow, total time.Duration) {
 	if len(rows) == 0 {
 		return
 	}
 
-	sort.Slice(rows, func(i, j int) bool {
-		if rows[i].hours != rows[j].hours {
-			return rows[i].hours > rows[j].hours
-		}
-		return rows[i].label < rows[j].label
-	})
-
 	data := make([][]string, len(rows))
 	for i, r := range rows {
-		pct := 0.0
-		if total > 0 {
-			pct = (r.hours / total) * 100
-		}
 		data[i] = []string{
 			r.label,
-			formatDuration(r.hours),
-			fmt.Sprintf("%.0f%%", pct),
+			formatDuration(r.shown),
+			fmt.Sprintf("%.0f%%", sharePercent(r.d, total)),
 		}
 	}
 
@@ -185,45 +190,17 @@ func writeColorVerticalChart(file *os.File, title string, columns []chartColumn,
 	fmt.Fprintln(file)
 }
 
-// writeColorWeekdayChart renders a colored Sun–Sat column chart of daily totals.
-func writeColorWeekdayChart(file *os.File, week model.WeekData) {
-	dayTotals := make(map[time.Weekday]float64)
-	for _, task := range week.Tasks {
-		for day, hours := range task.DayTotals {
-			dayTotals[day] += hours
-		}
-	}
-
-	days := []time.Weekday{
-		time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
-		time.Thursday, time.Friday, time.Saturday,
-	}
-
-	var max float64
-	for _, day := range days {
-		if dayTotals[day] > max {
-			max = dayTotals[day]
-		}
-	}
-	if max <= 0 {
-		return
-	}
-
-	columns := make([]chartColumn, len(days))
-	for i, day := range days {
-		hours := dayTotals[day]
-		columns[i] = chartColumn{
-			top:    day.String()[:3],
-			bottom: compactDuration(hours),
-			ratio:  hours / max,
-		}
+// writeColorWeekdayChart renders a colored column chart of daily totals,
+// starting on the week's first day.
+func writeColorWeekdayChart(file *os.File, week model.WeekData, opts Options) {
+	if columns, peak := weekdayColumns(week, opts); len(columns) > 0 {
+		writeColorVerticalChart(file, "Daily Trend", columns, peak)
 	}
-	writeColorVerticalChart(file, "Daily Trend", columns, formatDuration(max))
 }
 
 // writeColorCategoryTable prints a category's tasks as a bordered table with
 // the project column tinted by its stable color.
-func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSummary) {
+func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSummary, opts Options) {
 	fmt.Fprintln(file, headerStyle.Render(title))
 	if len(tasks) == 0 {
 		fmt.Fprintln(file, emptyStyle.Render("No entries found."))
@@ -233,13 +210,31 @@ func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSumm
 
 	sorted := sortTasksByProject(tasks)
 
-	rows := make([][]string, len(sorted))
+	// Annotations follow their task as sub-rows; noteRows marks them so the
+	// style func can dim them.
+	rows := make([][]string, 0, len(sorted))
+	noteRows := make(map[int]bool)
+	shown := roundTasks(sorted, opts)
+	billed := roundBilledTasks(sorted, opts)
+	headers := []string{"Project", "Task", "Time", "Sessions"}
+	if opts.ShowBilled {
+		headers = []string{"Project", "Task", "Tracked", "Billed", "Sessions"}
+	}
 	for i, t := range sorted {
-		rows[i] = []string{
+		row := []string{
 			truncate(projectName(t), 24),
 			truncate(t.Description, 55),
-			formatDuration(t.TotalTime),
-			fmt.Sprintf("%d", t.Sessions),
+			formatDuration(shown[i]),
+		}
+		if opts.ShowBilled {
+			row = append(row, formatDuration(billed[i]))
+		}
+		rows = append(rows, append(row, fmt.Sprintf("%d", t.Sessions)))
+		for _, note := range t.Annotations {
+			noteRows[len(rows)] = true
+			noteRow := make([]string, len(headers))
+			noteRow[1] = "↳ " + truncate(note, 53)
+			rows = append(rows, noteRow)
 		}
 	}
 
@@ -249,7 +244,7 @@ func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSumm
 	tbl := table.New().
 		Border(lipgloss.RoundedBorder()).
 		BorderStyle(lipgloss.NewStyle().Foreground(colorBorder)).
-		Headers("Project", "Task", "Time", "Sessions").
+		Headers(headers...).
 		Rows(rows...).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			if row == table.HeaderRow {
@@ -259,10 +254,13 @@ func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSumm
 				return headerCell
 			}
 			style := baseCell
-			switch col {
-			case 0:
+			if noteRows[row] {
+				return style.Italic(true).Foreground(colorSubtle)
+			}
+			switch {
+			case col == 0:
 				style = style.Foreground(colorProject)
-			case 2, 3:
+			case col >= 2:
 				style = style.Align(lipgloss.Right)
 			}
 			return style
@@ -272,28 +270,28 @@ func writeColorCategoryTable(file *os.File, title string, tasks []model.TaskSumm
 	fmt.Fprintln(file)
 }
 
-func writeColorCategories(file *os.File, tasks []model.TaskSummary) {
-	categorized := groupTasksByCategory(tasks)
-	writeColorCategoryTable(file, "Dev", categorized[categoryDev])
-	writeColorCategoryTable(file, "Meetings", categorized[categoryMeetings])
-	writeColorCategoryTable(file, "Knowledge", categorized[categoryKnowledge])
-	writeColorCategoryTable(file, "Misc", categorized[categoryMisc])
+func writeColorCategories(file *os.File, tasks []model.TaskSummary, opts Options) {
+	categorized := groupTasksByCategory(tasks, opts)
+	for _, category := range opts.Categories {
+		writeColorCategoryTable(file, categoryTitle(category), categorized[category], opts)
+	}
 }
 
 // WeekReportANSI renders a week report as styled terminal output.
-func WeekReportANSI(file *os.File, week model.WeekData, birthdayMonth time.Month, birthdayDay int) {
-	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("Week %d", birthdayWeekNumber(week.Start, birthdayMonth, birthdayDay))))
+func WeekReportANSI(file *os.File, week model.WeekData, opts Options) {
+	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("Week %d", opts.weekNumber(week))))
 	fmt.Fprintln(file, dateStyle.Render(fmt.Sprintf("%s → %s",
 		week.Start.Format("Mon, Jan 2"), week.End.Format("Mon, Jan 2"))))
-	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(formatDuration(week.Total)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(opts.total(week.Total, week.Billed)))
 
-	writeColorWeekdayChart(file, week)
+	writeColorWeekdayChart(file, week, opts)
 
 	if len(week.ByProject) > 0 {
-		writeColorShareChart(file, "Projects", week.ByProject, week.Total)
+		writeColorProjectChart(file, week.ByProject, week.Total, opts)
 	}
+	writeColorDimensionCharts(file, week.ByDimension, week.Total, opts)
 	if len(week.ByTag) > 0 {
-		writeColorShareChart(file, "Categories", week.ByTag, week.Total)
+		writeColorShareChart(file, "Categories", week.ByTag, week.Total, opts)
 	}
 
 	if len(week.Tasks) == 0 {
@@ -301,55 +299,37 @@ func WeekReportANSI(file *os.File, week model.WeekData, birthdayMonth time.Month
 		return
 	}
 
-	writeColorCategories(file, week.Tasks)
+	writeColorCategories(file, week.Tasks, opts)
 }
 
 // writeColorWeekTrend renders a week-over-week chart: vertical columns when
 // they fit, otherwise colored horizontal bars (e.g. a full-year range).
-func writeColorWeekTrend(file *os.File, weeks []model.WeekData, birthdayMonth time.Month, birthdayDay int) {
-	if len(weeks) < 2 {
-		return
-	}
-
-	var max float64
-	for _, w := range weeks {
-		if w.Total > max {
-			max = w.Total
-		}
-	}
-	if max <= 0 {
+func writeColorWeekTrend(file *os.File, weeks []model.WeekData, opts Options) {
+	columns, shown, peak := weekTrendColumns(weeks, opts)
+	if len(columns) == 0 {
 		return
 	}
 
-	columns := make([]chartColumn, len(weeks))
-	for i, w := range weeks {
-		columns[i] = chartColumn{
-			top:    fmt.Sprintf("W%d", birthdayWeekNumber(w.Start, birthdayMonth, birthdayDay)),
-			bottom: compactDuration(w.Total),
-			ratio:  w.Total / max,
-		}
-	}
-
 	if verticalChartWidth(columns) <= maxVerticalWidth {
-		writeColorVerticalChart(file, "Weekly Trend", columns, formatDuration(max))
+		writeColorVerticalChart(file, "Weekly Trend", columns, peak)
 		return
 	}
 
 	labels := make([]string, len(weeks))
 	labelWidth := 0
 	for i, w := range weeks {
-		labels[i] = fmt.Sprintf("W%d %s", birthdayWeekNumber(w.Start, birthdayMonth, birthdayDay), w.Start.Format("Jan 2"))
+		labels[i] = fmt.Sprintf("W%d %s", opts.weekNumber(w), w.Start.Format("Jan 2"))
 		if n := len([]rune(labels[i])); n > labelWidth {
 			labelWidth = n
 		}
 	}
 
 	fmt.Fprintln(file, headerStyle.Render("Weekly Trend"))
-	for i, w := range weeks {
+	for i := range weeks {
 		fmt.Fprintf(file, "%s  %s  %s\n",
 			subtleStyle.Render(fmt.Sprintf("%-*s", labelWidth, labels[i])),
-			renderColorBar(w.Total/max, accentColor),
-			fmt.Sprintf("%7s", formatDuration(w.Total)))
+			renderColorBar(columns[i].ratio, accentColor),
+			fmt.Sprintf("%7s", formatDuration(shown[i])))
 	}
 	fmt.Fprintln(file)
 }
@@ -367,56 +347,25 @@ func weekDateRange(start, end time.Time) string {
 // and one column per category (plus a Total column), so each week's category
 // mix is visible at a glance. Weeks are rows so the table stays a bounded width
 // regardless of how many weeks the report spans (a year just grows downward).
-func writeColorWeeklyCategoryMatrix(file *os.File, weeks []model.WeekData, birthdayMonth time.Month, birthdayDay int) {
-	categoryTotals := make(map[string]float64)
-	for _, w := range weeks {
-		for tag, hours := range w.ByTag {
-			categoryTotals[tag] += hours
-		}
-	}
-	if len(categoryTotals) == 0 {
+func writeColorWeeklyCategoryMatrix(file *os.File, weeks []model.WeekData, opts Options) {
+	headers, rows := weeklyCategoryMatrix(weeks, opts)
+	if len(headers) == 0 {
 		return
 	}
+	writeColorMatrix(file, "Weekly Categories", headers, rows)
+}
 
-	categories := make([]string, 0, len(categoryTotals))
-	for cat := range categoryTotals {
-		categories = append(categories, cat)
-	}
-	sort.Slice(categories, func(i, j int) bool {
-		if categoryTotals[categories[i]] != categoryTotals[categories[j]] {
-			return categoryTotals[categories[i]] > categoryTotals[categories[j]]
-		}
-		return categories[i] < categories[j]
-	})
-
-	cell := func(hours float64) string {
-		if hours <= 0 {
-			return "—"
-		}
-		return formatDuration(hours)
-	}
-
-	headers := make([]string, 0, len(categories)+2)
-	headers = append(headers, "Week")
-	headers = append(headers, categories...)
-	headers = append(headers, "Total")
-
-	rows := make([][]string, len(weeks))
-	for i, w := range weeks {
-		row := make([]string, 0, len(categories)+2)
-		row = append(row, fmt.Sprintf("W%d (%s)", birthdayWeekNumber(w.Start, birthdayMonth, birthdayDay), weekDateRange(w.Start, w.End)))
-		for _, cat := range categories {
-			row = append(row, cell(w.ByTag[cat]))
-		}
-		row = append(row, formatDuration(w.Total))
-		rows[i] = row
-	}
-
+// writeColorMatrix draws a table whose first column labels the rows and whose
+// last column holds row totals; every other cell is right-aligned. An empty
+// title omits the heading.
+func writeColorMatrix(file *os.File, title string, headers []string, rows [][]string) {
 	headerCell := lipgloss.NewStyle().Bold(true).Foreground(colorTableHeader).Padding(0, 1)
 	baseCell := lipgloss.NewStyle().Padding(0, 1)
-	totalCol := len(categories) + 1
+	totalCol := len(headers) - 1
 
-	fmt.Fprintln(file, headerStyle.Render("Weekly Categories"))
+	if title != "" {
+		fmt.Fprintln(file, headerStyle.Render(title))
+	}
 	tbl := table.New().
 		Border(lipgloss.RoundedBorder()).
 		BorderStyle(lipgloss.NewStyle().Foreground(colorBorder)).
@@ -445,37 +394,114 @@ func writeColorWeeklyCategoryMatrix(file *os.File, weeks []model.WeekData, birth
 	fmt.Fprintln(file)
 }
 
+// PivotReportANSI renders a pivot as a matrix table with a closing Total row.
+func PivotReportANSI(file *os.File, pivot model.Pivot, opts Options) {
+	fmt.Fprintln(file, titleStyle.Render(pivotTitle(pivot)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(opts.duration(pivot.Total)))
+
+	if len(pivot.Rows) == 0 {
+		fmt.Fprintln(file, emptyStyle.Render("No entries found."))
+		return
+	}
+
+	headers, rows := pivotTable(pivot, "—", opts)
+	writeColorMatrix(file, "", headers, rows)
+}
+
+// BillingReportANSI renders a billing report as a client → project → task
+// table followed by the subtotal, tax lines, total and invoiced split.
+func BillingReportANSI(file *os.File, report model.BillingReport, opts Options) {
+	fmt.Fprintln(file, titleStyle.Render(billingTitle(report)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(formatAmount(report.Total, report.Currency)))
+
+	if len(report.Clients) == 0 {
+		fmt.Fprintln(file, emptyStyle.Render("No billable entries found."))
+		fmt.Fprintln(file)
+	} else {
+		headers, rows, levels := billingTable(report, "  ", opts)
+		headerCell := lipgloss.NewStyle().Bold(true).Foreground(colorTableHeader).Padding(0, 1)
+		baseCell := lipgloss.NewStyle().Padding(0, 1)
+
+		tbl := table.New().
+			Border(lipgloss.RoundedBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(colorBorder)).
+			Headers(headers...).
+			Rows(rows...).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == table.HeaderRow {
+					if col >= 1 {
+						return headerCell.Align(lipgloss.Right)
+					}
+					return headerCell
+				}
+				style := baseCell
+				if col >= 1 {
+					style = style.Align(lipgloss.Right)
+				}
+				switch levels[row] {
+				case billingClient:
+					style = style.Bold(true).Foreground(colorTitle)
+				case billingProject:
+					style = style.Foreground(colorProject)
+				}
+				if col == len(headers)-1 {
+					style = style.Foreground(colorShare)
+				}
+				return style
+			})
+
+		fmt.Fprintln(file, tbl.Render())
+		fmt.Fprintln(file)
+	}
+
+	lines := billingSummary(report, opts)
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line[0]))
+	}
+	for _, line := range lines {
+		label := projectStyle.Render(fmt.Sprintf("%-*s", width+1, line[0]+":"))
+		value := shareStyle.Render(line[1])
+		if line[0] == "Total" {
+			value = totalStyle.Render(line[1])
+		}
+		fmt.Fprintf(file, "%s %s\n", label, value)
+	}
+	fmt.Fprintln(file)
+}
+
 // aggregateWeeks rolls per-week category (tag) and project totals up to a
 // parent total.
-func aggregateWeeks(weeks []model.WeekData) (tags, projects map[string]float64) {
-	tags = make(map[string]float64)
-	projects = make(map[string]float64)
+func aggregateWeeks(weeks []model.WeekData) (tags, projects map[string]time.Duration) {
+	tags = make(map[string]time.Duration)
+	projects = make(map[string]time.Duration)
 	for _, week := range weeks {
-		for tag, hours := range week.ByTag {
-			tags[tag] += hours
+		for tag, d := range week.ByTag {
+			tags[tag] += d
 		}
-		for project, hours := range week.ByProject {
-			projects[project] += hours
+		for project, d := range week.ByProject {
+			projects[project] += d
 		}
 	}
 	return tags, projects
 }
 
 // MonthReportANSI renders a month report as styled terminal output.
-func MonthReportANSI(file *os.File, month model.MonthData, year int, birthdayMonth time.Month, birthdayDay int) {
+func MonthReportANSI(file *os.File, month model.MonthData, year int, opts Options) {
 	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("%s %d", month.Month.String(), year)))
-	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(formatDuration(month.Total)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(opts.total(month.Total, month.Billed)))
 
 	tags, projects := aggregateWeeks(month.Weeks)
 
 	if len(month.Weeks) > 0 {
-		writeColorWeekTrend(file, month.Weeks, birthdayMonth, birthdayDay)
+		writeColorWeekTrend(file, month.Weeks, opts)
 	}
 	if len(projects) > 0 {
-		writeColorShareChart(file, "Projects", projects, month.Total)
+		writeColorProjectChart(file, projects, month.Total, opts)
 	}
+	writeColorDimensionCharts(file, aggregateWeekDimensions(month.Weeks), month.Total, opts)
 	if len(tags) > 0 {
-		writeColorShareChart(file, "Categories", tags, month.Total)
+		writeColorShareChart(file, "Categories", tags, month.Total, opts)
 	}
 
 	if len(month.Weeks) == 0 {
@@ -483,25 +509,26 @@ func MonthReportANSI(file *os.File, month model.MonthData, year int, birthdayMon
 		return
 	}
 
-	writeColorWeeklyCategoryMatrix(file, month.Weeks, birthdayMonth, birthdayDay)
+	writeColorWeeklyCategoryMatrix(file, month.Weeks, opts)
 }
 
 // RangeReportANSI renders a custom date-range report as styled terminal output.
-func RangeReportANSI(file *os.File, report model.MonthData, start, end time.Time, birthdayMonth time.Month, birthdayDay int) {
+func RangeReportANSI(file *os.File, report model.MonthData, start, end time.Time, opts Options) {
 	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("%s → %s",
 		start.Format("Jan 2, 2006"), end.AddDate(0, 0, -1).Format("Jan 2, 2006"))))
-	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(formatDuration(report.Total)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(opts.total(report.Total, report.Billed)))
 
 	tags, projects := aggregateWeeks(report.Weeks)
 
 	if len(report.Weeks) > 0 {
-		writeColorWeekTrend(file, report.Weeks, birthdayMonth, birthdayDay)
+		writeColorWeekTrend(file, report.Weeks, opts)
 	}
 	if len(projects) > 0 {
-		writeColorShareChart(file, "Projects", projects, report.Total)
+		writeColorProjectChart(file, projects, report.Total, opts)
 	}
+	writeColorDimensionCharts(file, aggregateWeekDimensions(report.Weeks), report.Total, opts)
 	if len(tags) > 0 {
-		writeColorShareChart(file, "Categories", tags, report.Total)
+		writeColorShareChart(file, "Categories", tags, report.Total, opts)
 	}
 
 	if len(report.Weeks) == 0 {
@@ -509,20 +536,21 @@ func RangeReportANSI(file *os.File, report model.MonthData, start, end time.Time
 		return
 	}
 
-	writeColorWeeklyCategoryMatrix(file, report.Weeks, birthdayMonth, birthdayDay)
+	writeColorWeeklyCategoryMatrix(file, report.Weeks, opts)
 }
 
 // DayReportANSI renders a single-day report as styled terminal output.
-func DayReportANSI(file *os.File, report model.DayReport, birthdayMonth time.Month, birthdayDay int) {
-	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("Day %d", birthdayDayNumber(report.Date, birthdayMonth, birthdayDay))))
+func DayReportANSI(file *os.File, report model.DayReport, opts Options) {
+	fmt.Fprintln(file, titleStyle.Render(fmt.Sprintf("Day %d", birthdayDayNumber(report.Date, opts.BirthdayMonth, opts.BirthdayDay))))
 	fmt.Fprintln(file, dateStyle.Render(report.Date.Format("Monday, Jan 2, 2006")))
-	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(formatDuration(report.Total)))
+	fmt.Fprintf(file, "%s %s\n\n", projectStyle.Render("Total:"), totalStyle.Render(opts.total(report.Total, report.Billed)))
 
 	if len(report.ByProject) > 0 {
-		writeColorShareChart(file, "Projects", report.ByProject, report.Total)
+		writeColorProjectChart(file, report.ByProject, report.Total, opts)
 	}
+	writeColorDimensionCharts(file, report.ByDimension, report.Total, opts)
 	if len(report.ByTag) > 0 {
-		writeColorShareChart(file, "Categories", report.ByTag, report.Total)
+		writeColorShareChart(file, "Categories", report.ByTag, report.Total, opts)
 	}
 
 	if len(report.Tasks) == 0 {
@@ -530,5 +558,5 @@ func DayReportANSI(file *os.File, report model.DayReport, birthdayMonth time.Mon
 		return
 	}
 
-	writeColorCategories(file, report.Tasks)
+	writeColorCategories(file, report.Tasks, opts)
 }
@@ -5,6 +5,7 @@ import (
 	"math"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,7 +66,8 @@ func renderBar(ratio float64) string {
 
 type chartRow struct {
 	label string
-	hours float64
+	d     time.Duration
+	shown time.Duration // d rounded for display
 }
 
 type chartColumn struct {
@@ -162,13 +164,13 @@ func writeVerticalChart(file *os.File, title string, columns []chartColumn, peak
 	fmt.Fprintf(file, "```\n")
 }
 
-// compactDuration formats hours like "2h42m" / "45m" / "" (for zero) so values
-// fit under a narrow vertical column.
-func compactDuration(hours float64) string {
-	if hours <= 0 {
+// compactDuration formats a rounded duration like "2h42m" / "45m" / "" (for
+// zero) so values fit under a narrow vertical column.
+func compactDuration(d time.Duration) string {
+	if d <= 0 {
 		return ""
 	}
-	totalMinutes := int(hours * 60)
+	totalMinutes := int(d.Round(time.Minute) / time.Minute)
 	h := totalMinutes / 60
 	m := totalMinutes % 60
 	if h == 0 {
@@ -184,26 +186,328 @@ func compactDuration(hours float64) string {
 // fenced code block so glow preserves alignment. Bars are scaled to the
 // largest value (not the total) so the leader fills the track and differences
 // stay legible; the share percentage is taken against total.
-func writeShareChart(file *os.File, title string, values map[string]float64, total float64) {
-	rows := make([]chartRow, 0, len(values))
-	var max float64
-	for label, hours := range values {
-		rows = append(rows, chartRow{label: label, hours: hours})
-		if hours > max {
-			max = hours
+func writeShareChart(file *os.File, title string, values map[string]time.Duration, total time.Duration, opts Options) {
+	writeShareRows(file, title, sortedShareRows(values, total, opts), total)
+}
+
+// writeProjectShareChart renders the Projects share chart as an indented
+// tree, so a client's total sits above its products and components.
+func writeProjectShareChart(file *os.File, byProject map[string]time.Duration, total time.Duration, opts Options) {
+	writeShareRows(file, "Projects", projectRows(byProject, total, opts, "  "), total)
+}
+
+// writeDimensionCharts renders a share chart for each configured dimension
+// that occurs in byDimension.
+func writeDimensionCharts(file *os.File, byDimension map[string]map[string]time.Duration, total time.Duration, opts Options) {
+	for _, dimension := range opts.Dimensions {
+		if values := byDimension[dimension]; len(values) > 0 {
+			writeShareChart(file, categoryTitle(dimension), values, total, opts)
+			fmt.Fprintf(file, "\n")
 		}
 	}
-	if len(rows) == 0 || max <= 0 {
-		return
+}
+
+// aggregateWeekDimensions rolls per-week dimension totals up to a parent
+// total.
+func aggregateWeekDimensions(weeks []model.WeekData) map[string]map[string]time.Duration {
+	byDimension := make(map[string]map[string]time.Duration)
+	for _, week := range weeks {
+		for dimension, values := range week.ByDimension {
+			if byDimension[dimension] == nil {
+				byDimension[dimension] = make(map[string]time.Duration)
+			}
+			for value, d := range values {
+				byDimension[dimension][value] += d
+			}
+		}
+	}
+	return byDimension
+}
+
+// pivotTitle names a pivot by its axes, e.g. "Client by Week".
+func pivotTitle(pivot model.Pivot) string {
+	return fmt.Sprintf("%s by %s", categoryTitle(pivot.RowDimension), categoryTitle(pivot.ColumnDimension))
+}
+
+// pivotTable lays a pivot out as text cells: a header row, one row per pivot
+// row with its total last, and a closing Total row. Cells without time show
+// empty. Each row is rounded to add up to its rounded total, as are the row
+// totals and the Total row to the grand total; columns may be a minute off.
+func pivotTable(pivot model.Pivot, empty string, opts Options) (headers []string, rows [][]string) {
+	cell := func(d time.Duration) string {
+		if d <= 0 {
+			return empty
+		}
+		return formatDuration(d)
+	}
+
+	headers = append(headers, categoryTitle(pivot.RowDimension))
+	headers = append(headers, pivot.Columns...)
+	headers = append(headers, "Total")
+
+	rowTotals := opts.Rounding.roundRows(pivot.RowTotals, pivot.Total)
+	for i, label := range pivot.Rows {
+		row := []string{label}
+		for _, d := range opts.Rounding.roundRowsTo(pivot.Cells[i], pivot.RowTotals[i], rowTotals[i]) {
+			row = append(row, cell(d))
+		}
+		rows = append(rows, append(row, formatDuration(rowTotals[i])))
+	}
+	totals := []string{"Total"}
+	for _, d := range opts.Rounding.roundRows(pivot.ColumnTotals, pivot.Total) {
+		totals = append(totals, cell(d))
+	}
+	rows = append(rows, append(totals, opts.duration(pivot.Total)))
+	return headers, rows
+}
+
+// weeklyCategoryMatrix lays weeks out against categories: a header row, then
+// one row per week with its time in each category (most time first) and its
+// total. It returns no headers when no week has a category.
+func weeklyCategoryMatrix(weeks []model.WeekData, opts Options) (headers []string, rows [][]string) {
+	categories := weekCategories(weeks)
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	cell := func(d time.Duration) string {
+		if d <= 0 {
+			return "—"
+		}
+		return formatDuration(d)
+	}
+
+	headers = make([]string, 0, len(categories)+2)
+	headers = append(headers, "Week")
+	headers = append(headers, categories...)
+	headers = append(headers, "Total")
+
+	rows = make([][]string, len(weeks))
+	for i, w := range weeks {
+		durations := make([]time.Duration, len(categories))
+		for j, cat := range categories {
+			durations[j] = w.ByTag[cat]
+		}
+		row := make([]string, 0, len(categories)+2)
+		row = append(row, fmt.Sprintf("W%d (%s)", opts.weekNumber(w), weekDateRange(w.Start, w.End)))
+		for _, d := range opts.Rounding.roundRows(durations, w.Total) {
+			row = append(row, cell(d))
+		}
+		row = append(row, opts.duration(w.Total))
+		rows[i] = row
+	}
+
+	return headers, rows
+}
+
+// weekCategories lists the categories of the weeks, most time first.
+func weekCategories(weeks []model.WeekData) []string {
+	categoryTotals := make(map[string]time.Duration)
+	for _, w := range weeks {
+		for tag, d := range w.ByTag {
+			categoryTotals[tag] += d
+		}
+	}
+
+	categories := make([]string, 0, len(categoryTotals))
+	for cat := range categoryTotals {
+		categories = append(categories, cat)
 	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categoryTotals[categories[i]] != categoryTotals[categories[j]] {
+			return categoryTotals[categories[i]] > categoryTotals[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
+// billingTitle names a billing report by its range.
+func billingTitle(report model.BillingReport) string {
+	if report.Start.IsZero() || report.End.IsZero() {
+		return "Billing"
+	}
+	return fmt.Sprintf("Billing: %s → %s",
+		report.Start.Format("Jan 2, 2006"), report.End.AddDate(0, 0, -1).Format("Jan 2, 2006"))
+}
+
+// billingLevel is the depth of a billing table row.
+type billingLevel int
+
+const (
+	billingClient billingLevel = iota
+	billingProject
+	billingTask
+)
+
+// billingTable lays a billing report out as text cells: a row per client,
+// its projects beneath it with their rate, and their tasks beneath those,
+// each level indented by indent. Times are rounded to add up to their parent.
+func billingTable(report model.BillingReport, indent string, opts Options) (headers []string, rows [][]string, levels []billingLevel) {
+	headers = []string{"Client / Project / Task", "Sessions", "Tracked", "Billed", "Rate", "Amount"}
+	add := func(level billingLevel, label, sessions string, tracked, billed time.Duration, rate, amount string) {
+		rows = append(rows, []string{strings.Repeat(indent, int(level)) + label, sessions,
+			formatDuration(tracked), formatDuration(billed), rate, amount})
+		levels = append(levels, level)
+	}
+	round := func(values []time.Duration, total, shownTotal time.Duration) []time.Duration {
+		return opts.Rounding.roundRowsTo(values, total, shownTotal)
+	}
+
+	clientTracked := make([]time.Duration, len(report.Clients))
+	clientBilled := make([]time.Duration, len(report.Clients))
+	for i, c := range report.Clients {
+		clientTracked[i], clientBilled[i] = c.Tracked, c.Billed
+	}
+	clientTracked = opts.Rounding.roundRows(clientTracked, report.Tracked)
+	clientBilled = opts.Rounding.roundRows(clientBilled, report.Billed)
+
+	for i, c := range report.Clients {
+		add(billingClient, c.Name, "", clientTracked[i], clientBilled[i], "", formatAmount(c.Amount, report.Currency))
 
+		projectTracked := make([]time.Duration, len(c.Projects))
+		projectBilled := make([]time.Duration, len(c.Projects))
+		for j, p := range c.Projects {
+			projectTracked[j], projectBilled[j] = p.Tracked, p.Billed
+		}
+		projectTracked = round(projectTracked, c.Tracked, clientTracked[i])
+		projectBilled = round(projectBilled, c.Billed, clientBilled[i])
+
+		for j, p := range c.Projects {
+			add(billingProject, p.Name, "", projectTracked[j], projectBilled[j],
+				formatAmount(p.Rate, report.Currency)+"/h", formatAmount(p.Amount, report.Currency))
+
+			taskTracked := make([]time.Duration, len(p.Tasks))
+			taskBilled := make([]time.Duration, len(p.Tasks))
+			for k, t := range p.Tasks {
+				taskTracked[k], taskBilled[k] = t.Tracked, t.Billed
+			}
+			taskTracked = round(taskTracked, p.Tracked, projectTracked[j])
+			taskBilled = round(taskBilled, p.Billed, projectBilled[j])
+
+			for k, t := range p.Tasks {
+				add(billingTask, truncate(t.Description, 55), strconv.Itoa(t.Sessions), taskTracked[k], taskBilled[k],
+					"", formatAmount(t.Amount, report.Currency))
+			}
+		}
+	}
+	return headers, rows, levels
+}
+
+// billingSummary returns the closing lines of a billing report as label and
+// value pairs: the subtotal, each tax, the total, the invoiced split and any
+// non-billable time.
+func billingSummary(report model.BillingReport, opts Options) [][2]string {
+	lines := [][2]string{{"Subtotal", formatAmount(report.Subtotal, report.Currency)}}
+	for _, tax := range report.Taxes {
+		label := fmt.Sprintf("%s %s%%", tax.Name, strconv.FormatFloat(tax.Percent, 'f', -1, 64))
+		lines = append(lines, [2]string{label, formatAmount(tax.Amount, report.Currency)})
+	}
+	lines = append(lines,
+		[2]string{"Total", formatAmount(report.Total, report.Currency)},
+		[2]string{"Invoiced", fmt.Sprintf("%s (%s)",
+			formatAmount(report.Invoiced.Amount, report.Currency), opts.duration(report.Invoiced.Billed))},
+		[2]string{"Not yet invoiced", fmt.Sprintf("%s (%s)",
+			formatAmount(report.Uninvoiced.Amount, report.Currency), opts.duration(report.Uninvoiced.Billed))},
+	)
+	if report.NonBillable > 0 {
+		lines = append(lines, [2]string{"Non-billable", opts.duration(report.NonBillable)})
+	}
+	return lines
+}
+
+// formatAmount formats an amount in cents with two decimals and thousands
+// separators, followed by the currency when one is configured
+// ("1,234.50 EUR").
+func formatAmount(cents int64, currency string) string {
+	whole, fraction, _ := strings.Cut(formatCents(cents), ".")
+	var b strings.Builder
+	if rest, ok := strings.CutPrefix(whole, "-"); ok {
+		b.WriteByte('-')
+		whole = rest
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("." + fraction)
+	if currency != "" {
+		b.WriteString(" " + currency)
+	}
+	return b.String()
+}
+
+// formatCents formats an amount in cents as a plain decimal ("1234.50").
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// sortedShareRows turns labelled values into chart rows ordered by time
+// descending, then label, rounded as rows of total.
+func sortedShareRows(values map[string]time.Duration, total time.Duration, opts Options) []chartRow {
+	rows := make([]chartRow, 0, len(values))
+	for label, d := range values {
+		rows = append(rows, chartRow{label: label, d: d})
+	}
 	sort.Slice(rows, func(i, j int) bool {
-		if rows[i].hours != rows[j].hours {
-			return rows[i].hours > rows[j].hours
+		if rows[i].d != rows[j].d {
+			return rows[i].d > rows[j].d
 		}
 		return rows[i].label < rows[j].label
 	})
 
+	durations := make([]time.Duration, len(rows))
+	for i, r := range rows {
+		durations[i] = r.d
+	}
+	for i, shown := range opts.Rounding.roundRows(durations, total) {
+		rows[i].shown = shown
+	}
+	return rows
+}
+
+// projectRows flattens the project tree depth-first into chart rows, each
+// label indented by one indent per level. Levels below opts.ProjectDepth are
+// folded into their ancestor, whose time already includes them. Each level is
+// rounded as the rows of its parent.
+func projectRows(byProject map[string]time.Duration, total time.Duration, opts Options, indent string) []chartRow {
+	var rows []chartRow
+	var walk func(nodes []*model.ProjectNode, depth int, parent, shownParent time.Duration)
+	walk = func(nodes []*model.ProjectNode, depth int, parent, shownParent time.Duration) {
+		durations := make([]time.Duration, len(nodes))
+		for i, n := range nodes {
+			durations[i] = n.Total
+		}
+		shown := opts.Rounding.roundRowsTo(durations, parent, shownParent)
+		for i, n := range nodes {
+			rows = append(rows, chartRow{label: strings.Repeat(indent, depth) + n.Name, d: n.Total, shown: shown[i]})
+			if opts.ProjectDepth == 0 || depth+1 < opts.ProjectDepth {
+				walk(n.Children, depth+1, n.Total, shown[i])
+			}
+		}
+	}
+	walk(model.ProjectTree(byProject), 0, total, opts.Rounding.round(total))
+	return rows
+}
+
+// writeShareRows draws pre-ordered share rows; see writeShareChart.
+func writeShareRows(file *os.File, title string, rows []chartRow, total time.Duration) {
+	var max time.Duration
+	for _, r := range rows {
+		if r.d > max {
+			max = r.d
+		}
+	}
+	if len(rows) == 0 || max <= 0 {
+		return
+	}
+
 	labelWidth := 0
 	for _, r := range rows {
 		if n := len([]rune(r.label)); n > labelWidth {
@@ -214,86 +518,118 @@ func writeShareChart(file *os.File, title string, values map[string]float64, tot
 	fmt.Fprintf(file, "**%s**\n\n", title)
 	fmt.Fprintf(file, "```\n")
 	for _, r := range rows {
-		pct := 0.0
-		if total > 0 {
-			pct = (r.hours / total) * 100
-		}
 		fmt.Fprintf(file, "%-*s  %s  %7s  %3.0f%%\n",
 			labelWidth, r.label,
-			renderBar(r.hours/max),
-			formatDuration(r.hours),
-			pct)
+			renderBar(float64(r.d)/float64(max)),
+			formatDuration(r.shown),
+			sharePercent(r.d, total))
 	}
 	fmt.Fprintf(file, "```\n")
 }
 
-// writeWeekdayChart renders a Sun–Sat bar chart of daily totals for a single
-// week so the within-week rhythm is visible at a glance.
-func writeWeekdayChart(file *os.File, week model.WeekData) {
-	dayTotals := make(map[time.Weekday]float64)
-	for _, task := range week.Tasks {
-		for day, hours := range task.DayTotals {
-			dayTotals[day] += hours
-		}
+// writeWeekdayChart renders a bar chart of daily totals for a single week,
+// starting on its first day, so the within-week rhythm is visible at a glance.
+func writeWeekdayChart(file *os.File, week model.WeekData, opts Options) {
+	if columns, peak := weekdayColumns(week, opts); len(columns) > 0 {
+		writeVerticalChart(file, "Daily Trend", columns, peak)
 	}
+}
 
-	days := []time.Weekday{
-		time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
-		time.Thursday, time.Friday, time.Saturday,
+// weekdayColumns builds the columns of a week's daily chart, with days rounded
+// to add up to the week's total, and the peak label. It returns no columns
+// for an empty week.
+func weekdayColumns(week model.WeekData, opts Options) ([]chartColumn, string) {
+	dayTotals := make(map[time.Weekday]time.Duration)
+	for _, task := range week.Tasks {
+		for day, d := range task.DayTotals {
+			dayTotals[day] += d
+		}
 	}
 
-	var max float64
-	for _, day := range days {
-		if dayTotals[day] > max {
-			max = dayTotals[day]
-		}
+	days := weekdaysFrom(week.Start.Weekday())
+	durations := make([]time.Duration, len(days))
+	var max time.Duration
+	for i, day := range days {
+		durations[i] = dayTotals[day]
+		max = maxDuration(max, durations[i])
 	}
 	if max <= 0 {
-		return
+		return nil, ""
 	}
 
+	shown := opts.Rounding.roundRows(durations, week.Total)
+	var peak time.Duration
 	columns := make([]chartColumn, len(days))
 	for i, day := range days {
-		hours := dayTotals[day]
 		columns[i] = chartColumn{
 			top:    day.String()[:3],
-			bottom: compactDuration(hours),
-			ratio:  hours / max,
+			bottom: compactDuration(shown[i]),
+			ratio:  float64(durations[i]) / float64(max),
 		}
+		peak = maxDuration(peak, shown[i])
 	}
-
-	writeVerticalChart(file, "Daily Trend", columns, formatDuration(max))
+	return columns, formatDuration(peak)
 }
 
-// writeWeekTrend renders a week-over-week bar chart for a month/range report so
-// the shape of effort over time is visible at a glance. Weeks are assumed
-// chronological (build.groupByWeek sorts them).
-func writeWeekTrend(file *os.File, weeks []model.WeekData, birthdayMonth time.Month, birthdayDay int) {
+// weekTrendColumns builds the columns of a week-over-week chart, rounded to
+// add up to the weeks' total, and the peak label. It returns no columns when
+// there is nothing to compare.
+func weekTrendColumns(weeks []model.WeekData, opts Options) ([]chartColumn, []time.Duration, string) {
 	if len(weeks) < 2 {
-		return
+		return nil, nil, ""
 	}
 
-	var max float64
-	for _, w := range weeks {
-		if w.Total > max {
-			max = w.Total
-		}
+	durations := make([]time.Duration, len(weeks))
+	var max, total time.Duration
+	for i, w := range weeks {
+		durations[i] = w.Total
+		max = maxDuration(max, w.Total)
+		total += w.Total
 	}
 	if max <= 0 {
-		return
+		return nil, nil, ""
 	}
 
+	shown := opts.Rounding.roundRows(durations, total)
+	var peak time.Duration
 	columns := make([]chartColumn, len(weeks))
 	for i, w := range weeks {
 		columns[i] = chartColumn{
-			top:    fmt.Sprintf("W%d", birthdayWeekNumber(w.Start, birthdayMonth, birthdayDay)),
-			bottom: compactDuration(w.Total),
-			ratio:  w.Total / max,
+			top:    fmt.Sprintf("W%d", opts.weekNumber(w)),
+			bottom: compactDuration(shown[i]),
+			ratio:  float64(w.Total) / float64(max),
 		}
+		peak = maxDuration(peak, shown[i])
+	}
+	return columns, shown, formatDuration(peak)
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// sharePercent is d as a percentage of total, 0 for an empty total.
+func sharePercent(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(d) / float64(total) * 100
+}
+
+// writeWeekTrend renders a week-over-week bar chart for a month/range report so
+// the shape of effort over time is visible at a glance. Weeks are assumed
+// chronological (build.groupByWeek sorts them).
+func writeWeekTrend(file *os.File, weeks []model.WeekData, opts Options) {
+	columns, shown, peak := weekTrendColumns(weeks, opts)
+	if len(columns) == 0 {
+		return
 	}
 
 	if verticalChartWidth(columns) <= maxVerticalWidth {
-		writeVerticalChart(file, "Weekly Trend", columns, formatDuration(max))
+		writeVerticalChart(file, "Weekly Trend", columns, peak)
 		return
 	}
 
@@ -302,7 +638,7 @@ func writeWeekTrend(file *os.File, weeks []model.WeekData, birthdayMonth time.Mo
 	labels := make([]string, len(weeks))
 	labelWidth := 0
 	for i, w := range weeks {
-		labels[i] = fmt.Sprintf("W%d %s", birthdayWeekNumber(w.Start, birthdayMonth, birthdayDay), w.Start.Format("Jan 2"))
+		labels[i] = fmt.Sprintf("W%d %s", opts.weekNumber(w), w.Start.Format("Jan 2"))
 		if n := len([]rune(labels[i])); n > labelWidth {
 			labelWidth = n
 		}
@@ -310,11 +646,141 @@ func writeWeekTrend(file *os.File, weeks []model.WeekData, birthdayMonth time.Mo
 
 	fmt.Fprintf(file, "**Weekly Trend**\n\n")
 	fmt.Fprintf(file, "```\n")
-	for i, w := range weeks {
+	for i := range weeks {
 		fmt.Fprintf(file, "%-*s  %s  %7s\n",
 			labelWidth, labels[i],
-			renderBar(w.Total/max),
-			formatDuration(w.Total))
+			renderBar(columns[i].ratio),
+			formatDuration(shown[i]))
 	}
 	fmt.Fprintf(file, "```\n")
 }
+
+// Chart names, for selecting a report's charts. Dimension share charts are
+// named after their dimension.
+const (
+	ChartDaily            = "daily"
+	ChartWeekly           = "weekly"
+	ChartProjects         = "projects"
+	ChartCategories       = "categories"
+	ChartWeeklyCategories = "weekly-categories"
+)
+
+// ChartNames lists the names of every chart a report can have, given the
+// configured dimensions.
+func ChartNames(opts Options) []string {
+	names := []string{ChartDaily, ChartWeekly, ChartProjects, ChartCategories, ChartWeeklyCategories}
+	return append(names, opts.Dimensions...)
+}
+
+type chartKind int
+
+const (
+	columnChart chartKind = iota
+	shareChart
+	stackedChart
+)
+
+// Chart is one of a report's charts, laid out independently of the image
+// format that draws it.
+type Chart struct {
+	Name  string
+	Title string
+	kind  chartKind
+
+	columns []chartColumn // columnChart
+	peak    string
+
+	rows  []chartRow // shareChart
+	total time.Duration
+
+	stack stackedColumns // stackedChart
+}
+
+// stackedColumns are columns split into segments, one per series.
+type stackedColumns struct {
+	series  []string
+	labels  []string
+	values  [][]time.Duration // values[column][series]
+	totals  []string          // shown total above each column
+	largest time.Duration
+}
+
+// DayCharts returns the charts of a day report: its project, dimension and
+// category shares.
+func DayCharts(report model.DayReport, opts Options) []Chart {
+	return shareCharts(report.ByProject, report.ByTag, report.ByDimension, report.Total, opts)
+}
+
+// WeekCharts returns the charts of a week report: the daily trend, then its
+// shares.
+func WeekCharts(week model.WeekData, opts Options) []Chart {
+	var charts []Chart
+	if columns, peak := weekdayColumns(week, opts); len(columns) > 0 {
+		charts = append(charts, Chart{Name: ChartDaily, Title: "Daily Trend", kind: columnChart, columns: columns, peak: peak})
+	}
+	return append(charts, shareCharts(week.ByProject, week.ByTag, week.ByDimension, week.Total, opts)...)
+}
+
+// MonthCharts returns the charts of a month or range report: the weekly
+// trend, its shares and the weekly categories as stacked columns.
+func MonthCharts(report model.MonthData, opts Options) []Chart {
+	var charts []Chart
+	if columns, _, peak := weekTrendColumns(report.Weeks, opts); len(columns) > 0 {
+		charts = append(charts, Chart{Name: ChartWeekly, Title: "Weekly Trend", kind: columnChart, columns: columns, peak: peak})
+	}
+	tags, projects := aggregateWeeks(report.Weeks)
+	charts = append(charts, shareCharts(projects, tags, aggregateWeekDimensions(report.Weeks), report.Total, opts)...)
+	if stack, ok := weeklyCategoryColumns(report.Weeks, opts); ok {
+		charts = append(charts, Chart{Name: ChartWeeklyCategories, Title: "Weekly Categories", kind: stackedChart, stack: stack})
+	}
+	return charts
+}
+
+// shareCharts returns the project, dimension and category share charts that
+// have any time.
+func shareCharts(byProject, byTag map[string]time.Duration, byDimension map[string]map[string]time.Duration, total time.Duration, opts Options) []Chart {
+	var charts []Chart
+	add := func(name, title string, rows []chartRow) {
+		if len(rows) > 0 {
+			charts = append(charts, Chart{Name: name, Title: title, kind: shareChart, rows: rows, total: total})
+		}
+	}
+	// Drawn text collapses leading spaces, so levels are indented with
+	// no-break spaces.
+	add(ChartProjects, "Projects", projectRows(byProject, total, opts, "\u00a0\u00a0\u00a0"))
+	for _, dimension := range opts.Dimensions {
+		add(dimension, categoryTitle(dimension), sortedShareRows(byDimension[dimension], total, opts))
+	}
+	add(ChartCategories, "Categories", sortedShareRows(byTag, total, opts))
+	return charts
+}
+
+// weeklyCategoryColumns splits each week's time by category, like
+// weeklyCategoryMatrix. It reports false when no week has a category.
+func weeklyCategoryColumns(weeks []model.WeekData, opts Options) (stackedColumns, bool) {
+	categories := weekCategories(weeks)
+	if len(categories) == 0 {
+		return stackedColumns{}, false
+	}
+
+	stack := stackedColumns{series: categories}
+	totals := make([]time.Duration, len(weeks))
+	var total time.Duration
+	for i, w := range weeks {
+		values := make([]time.Duration, len(categories))
+		var sum time.Duration
+		for j, cat := range categories {
+			values[j] = w.ByTag[cat]
+			sum += values[j]
+		}
+		stack.labels = append(stack.labels, fmt.Sprintf("W%d", opts.weekNumber(w)))
+		stack.values = append(stack.values, values)
+		stack.largest = maxDuration(stack.largest, sum)
+		totals[i] = w.Total
+		total += w.Total
+	}
+	for _, d := range opts.Rounding.roundRows(totals, total) {
+		stack.totals = append(stack.totals, compactDuration(d))
+	}
+	return stack, true
+}
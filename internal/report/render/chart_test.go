@@ -0,0 +1,23 @@
+package render
+
+import "testing"
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		cents    int64
+		currency string
+		want     string
+	}{
+		{0, "", "0.00"},
+		{5, "EUR", "0.05 EUR"},
+		{123450, "EUR", "1,234.50 EUR"},
+		{100000000, "", "1,000,000.00"},
+		{-123456, "USD", "-1,234.56 USD"},
+		{-7, "", "-0.07"},
+	}
+	for _, tt := range tests {
+		if got := formatAmount(tt.cents, tt.currency); got != tt.want {
+			t.Errorf("formatAmount(%d, %q) = %q, want %q", tt.cents, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// newDelimitedWriter returns a writer separating fields with comma, ',' for
+// CSV or '\t' for TSV. CSV follows RFC 4180, CRLF line endings included; TSV
+// keeps plain newlines, as tab-separated tools expect.
+func newDelimitedWriter(file *os.File, comma rune) *csv.Writer {
+	w := csv.NewWriter(file)
+	w.Comma = comma
+	w.UseCRLF = comma == ','
+	return w
+}
+
+// exportHours formats a duration for spreadsheets: decimal hours with two
+// places (1.25), or h:mm (1:15) when opts.ClockHours is set, rounded to the
+// last place shown under the rounding policy.
+func exportHours(d time.Duration, opts Options) string {
+	d = opts.Rounding.roundIn(exportUnit(opts), d)
+	if !opts.ClockHours {
+		return strconv.FormatFloat(d.Hours(), 'f', 2, 64)
+	}
+	minutes := int64(d / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// exportUnit is the precision of exported durations: a hundredth of an hour
+// in decimal hours, a minute in h:mm.
+func exportUnit(opts Options) time.Duration {
+	if opts.ClockHours {
+		return time.Minute
+	}
+	return time.Hour / 100
+}
+
+// exportRows rounds the cells of an exported row or column like roundRowsTo,
+// so that under balanced rounding they add up to shownTotal as exported.
+func exportRows(rows []time.Duration, total, shownTotal time.Duration, opts Options) []time.Duration {
+	return opts.Rounding.roundRowsIn(exportUnit(opts), rows, total, shownTotal)
+}
+
+// PivotReportCSV writes a pivot for spreadsheets: a header row, one row per
+// pivot row, and a closing Total row.
+func PivotReportCSV(file *os.File, pivot model.Pivot, comma rune, opts Options) error {
+	w := newDelimitedWriter(file, comma)
+	hours := func(d time.Duration) string {
+		return exportHours(d, opts)
+	}
+
+	header := append([]string{pivot.RowDimension}, pivot.Columns...)
+	if err := w.Write(append(header, "total")); err != nil {
+		return err
+	}
+	shownTotal := opts.Rounding.roundIn(exportUnit(opts), pivot.Total)
+	rowTotals := exportRows(pivot.RowTotals, pivot.Total, shownTotal, opts)
+	for i, label := range pivot.Rows {
+		record := []string{label}
+		for _, d := range exportRows(pivot.Cells[i], pivot.RowTotals[i], rowTotals[i], opts) {
+			record = append(record, hours(d))
+		}
+		if err := w.Write(append(record, hours(rowTotals[i]))); err != nil {
+			return err
+		}
+	}
+	totals := []string{"total"}
+	for _, d := range exportRows(pivot.ColumnTotals, pivot.Total, shownTotal, opts) {
+		totals = append(totals, hours(d))
+	}
+	if err := w.Write(append(totals, hours(shownTotal))); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// BillingReportCSV writes a billing report for spreadsheets and invoicing
+// tools: one row per task with its hours, rate and amount, then subtotal,
+// tax, total and invoiced split rows that carry only an amount.
+func BillingReportCSV(file *os.File, report model.BillingReport, comma rune, opts Options) error {
+	w := newDelimitedWriter(file, comma)
+	hours := func(d time.Duration) string {
+		return exportHours(d, opts)
+	}
+
+	header := []string{"client", "project", "task", "sessions", "tracked_hours", "billed_hours", "rate", "amount", "currency"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, c := range report.Clients {
+		for _, p := range c.Projects {
+			for _, t := range p.Tasks {
+				record := []string{c.Name, p.Name, t.Description, strconv.Itoa(t.Sessions),
+					hours(t.Tracked), hours(t.Billed), formatCents(p.Rate), formatCents(t.Amount), report.Currency}
+				if err := w.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	summary := func(label string, amount int64) error {
+		return w.Write([]string{label, "", "", "", "", "", "", formatCents(amount), report.Currency})
+	}
+	if err := summary("subtotal", report.Subtotal); err != nil {
+		return err
+	}
+	for _, tax := range report.Taxes {
+		label := fmt.Sprintf("%s %s%%", tax.Name, strconv.FormatFloat(tax.Percent, 'f', -1, 64))
+		if err := summary(label, tax.Amount); err != nil {
+			return err
+		}
+	}
+	if err := summary("total", report.Total); err != nil {
+		return err
+	}
+	if err := summary("invoiced", report.Invoiced.Amount); err != nil {
+		return err
+	}
+	if err := summary("not invoiced", report.Uninvoiced.Amount); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// TasksCSV writes the task rows of a report for spreadsheets, week by week
+// like the week report's task tables: the week's first day, project,
+// description, tags, sessions, total (and billed) hours, then the hours of
+// each weekday.
+func TasksCSV(file *os.File, report model.MonthData, comma rune, opts Options) error {
+	w := newDelimitedWriter(file, comma)
+
+	days := weekdaysFrom(opts.WeekStart)
+	header := []string{"week", "project", "description", "tags", "sessions", "total"}
+	if opts.ShowBilled {
+		header = append(header, "billed")
+	}
+	for _, day := range days {
+		header = append(header, strings.ToLower(day.String()[:3]))
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, week := range report.Weeks {
+		for _, t := range sortTasksByProject(week.Tasks) {
+			tags := make([]string, 0, len(t.Tags))
+			for tag := range t.Tags {
+				tags = append(tags, tag)
+			}
+			total := opts.Rounding.roundIn(exportUnit(opts), t.TotalTime)
+			record := []string{week.Start.Format(time.DateOnly), t.Project, t.Description,
+				exportTags(tags), strconv.Itoa(t.Sessions), exportHours(total, opts)}
+			if opts.ShowBilled {
+				record = append(record, exportHours(t.BilledTime, opts))
+			}
+			dayTimes := make([]time.Duration, len(days))
+			for i, day := range days {
+				dayTimes[i] = t.DayTotals[day]
+			}
+			for i, d := range exportRows(dayTimes, t.TotalTime, total, opts) {
+				if dayTimes[i] > 0 {
+					record = append(record, exportHours(d, opts))
+				} else {
+					record = append(record, "")
+				}
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// SessionsCSV writes one row per session with its local start and end, its
+// hours (and billed hours), project, description, tags and annotation.
+func SessionsCSV(file *os.File, sessions []model.Session, comma rune, opts Options) error {
+	w := newDelimitedWriter(file, comma)
+
+	header := []string{"start", "end", "hours"}
+	if opts.ShowBilled {
+		header = append(header, "billed")
+	}
+	header = append(header, "project", "description", "tags", "annotation")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	const layout = "2006-01-02 15:04:05"
+	for _, s := range sessions {
+		record := []string{s.Start.Local().Format(layout), s.End.Local().Format(layout), exportHours(s.Tracked, opts)}
+		if opts.ShowBilled {
+			record = append(record, exportHours(s.Billed, opts))
+		}
+		record = append(record, s.Project, s.Description, exportTags(s.Tags), s.Annotation)
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// exportTags joins tags with commas, leaving out the project tag, which has a
+// column of its own.
+func exportTags(tags []string) string {
+	var kept []string
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, "project:") {
+			kept = append(kept, tag)
+		}
+	}
+	sort.Strings(kept)
+	return strings.Join(kept, ",")
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+func TestSessionsCSVQuoting(t *testing.T) {
+	start := time.Date(2026, time.September, 16, 9, 0, 0, 0, time.Local)
+	sessions := []model.Session{{
+		Start:       start,
+		End:         start.Add(90 * time.Minute),
+		Project:     "acme.api",
+		Description: `say "hi", then leave`,
+		Tags:        []string{"dev", "project:acme.api", "review"},
+		Annotation:  "two\nlines",
+		Tracked:     90 * time.Minute,
+	}}
+	tests := []struct {
+		name  string
+		comma rune
+		want  string
+	}{
+		{"csv", ',', "start,end,hours,project,description,tags,annotation\r\n" +
+			`2026-09-16 09:00:00,2026-09-16 10:30:00,1.50,acme.api,"say ""hi"", then leave","dev,review","two` + "\r\nlines\"\r\n"},
+		{"tsv", '\t', "start\tend\thours\tproject\tdescription\ttags\tannotation\n" +
+			"2026-09-16 09:00:00\t2026-09-16 10:30:00\t1.50\tacme.api\t\"say \"\"hi\"\", then leave\"\tdev,review\t\"two\nlines\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := output(t, func(f *os.File) error { return SessionsCSV(f, sessions, tt.comma, Options{}) })
+			if got != tt.want {
+				t.Errorf("SessionsCSV() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTasksCSVDaysAddUpToTotal(t *testing.T) {
+	week := model.WeekData{
+		Start: time.Date(2026, time.September, 13, 0, 0, 0, 0, time.Local),
+		Tasks: []model.TaskSummary{{
+			Description: "review",
+			Project:     "acme",
+			TotalTime:   time.Hour,
+			Sessions:    3,
+			DayTotals: map[time.Weekday]time.Duration{
+				time.Monday:    20 * time.Minute,
+				time.Tuesday:   20 * time.Minute,
+				time.Wednesday: 20 * time.Minute,
+			},
+		}},
+	}
+	report := model.MonthData{Weeks: []model.WeekData{week}, Total: time.Hour}
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"balanced", Options{}, "2026-09-13,acme,review,,3,1.00,,0.34,0.33,0.33,,,\r\n"},
+		{"nearest", Options{Rounding: RoundNearest}, "2026-09-13,acme,review,,3,1.00,,0.33,0.33,0.33,,,\r\n"},
+		{"clock hours", Options{ClockHours: true}, "2026-09-13,acme,review,,3,1:00,,0:20,0:20,0:20,,,\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := output(t, func(f *os.File) error { return TasksCSV(f, report, ',', tt.opts) })
+			_, row, _ := strings.Cut(got, "\r\n")
+			if row != tt.want {
+				t.Errorf("TasksCSV() row = %q, want %q", row, tt.want)
+			}
+		})
+	}
+}
+
+func TestPivotReportCSVAddsUp(t *testing.T) {
+	third := 20 * time.Minute
+	pivot := model.Pivot{
+		RowDimension: "project",
+		Rows:         []string{"acme", "globex"},
+		Columns:      []string{"mon", "tue", "wed"},
+		Cells: [][]time.Duration{
+			{third, third, third},
+			{third, 0, third + 18*time.Second},
+		},
+		RowTotals:    []time.Duration{time.Hour, 2*third + 18*time.Second},
+		ColumnTotals: []time.Duration{2 * third, third, 2*third + 18*time.Second},
+		Total:        time.Hour + 2*third + 18*time.Second,
+	}
+	want := "project,mon,tue,wed,total\r\n" +
+		"acme,0.34,0.33,0.33,1.00\r\n" +
+		"globex,0.33,0.00,0.34,0.67\r\n" +
+		"total,0.67,0.33,0.67,1.67\r\n"
+	got := output(t, func(f *os.File) error { return PivotReportCSV(f, pivot, ',', Options{}) })
+	if got != want {
+		t.Errorf("PivotReportCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
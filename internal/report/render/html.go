@@ -0,0 +1,203 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// The HTML reports are single offline files: inline CSS, charts as inline
+// SVG and no scripts, so they can be mailed or archived as they are. They
+// follow the structure of the ANSI reports.
+
+const htmlStyle = `body { margin: 2em auto; max-width: 60em; padding: 0 1em; color: #303030; font: 14px/1.4 Helvetica, Arial, sans-serif; }
+h1 { margin: 0; font-size: 1.8em; }
+h2 { margin: 1.6em 0 0.5em; color: #5f87af; font-size: 1.15em; }
+.date { margin: 0.2em 0; color: #8a8a8a; }
+.total { margin: 0.6em 0 1.2em; font-weight: bold; }
+.total span { color: #5faf87; }
+.empty { color: #8a8a8a; font-style: italic; }
+svg { display: block; max-width: 100%; height: auto; }
+table { border-collapse: collapse; margin: 0.4em 0 1em; }
+th, td { padding: 0.25em 0.7em; border-bottom: 1px solid #e4e4e4; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; color: #875f00; }
+.num { text-align: right; font-variant-numeric: tabular-nums; }
+.project { color: #5f87af; }
+tr.note td { border-bottom: none; color: #8a8a8a; font-style: italic; padding-top: 0; }
+@media print { h2 { break-after: avoid; } table, svg { break-inside: avoid; } }
+`
+
+// htmlPage collects a report's body; write wraps it in the document.
+type htmlPage struct {
+	title string
+	b     strings.Builder
+}
+
+func newHTMLPage(title, date, total string) *htmlPage {
+	p := &htmlPage{title: title}
+	fmt.Fprintf(&p.b, "<h1>%s</h1>\n", html.EscapeString(title))
+	if date != "" {
+		fmt.Fprintf(&p.b, "<p class=\"date\">%s</p>\n", html.EscapeString(date))
+	}
+	fmt.Fprintf(&p.b, "<p class=\"total\">Total: <span>%s</span></p>\n", html.EscapeString(total))
+	return p
+}
+
+func (p *htmlPage) heading(title string) {
+	fmt.Fprintf(&p.b, "<h2>%s</h2>\n", html.EscapeString(title))
+}
+
+func (p *htmlPage) empty(message string) {
+	fmt.Fprintf(&p.b, "<p class=\"empty\">%s</p>\n", html.EscapeString(message))
+}
+
+func (p *htmlPage) write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n"+
+		"<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n"+
+		"<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n%s</body>\n</html>\n",
+		html.EscapeString(p.title), htmlStyle, p.b.String())
+	return err
+}
+
+// charts adds each chart under its title.
+func (p *htmlPage) charts(charts []Chart) {
+	for _, c := range charts {
+		p.heading(c.Title)
+		p.b.WriteString(chartSVG(c))
+	}
+}
+
+// table adds a table whose first columns up to numFrom hold text and whose
+// remaining columns are right-aligned numbers. Rows listed in notes hold an
+// annotation in their second cell, spanning the rest of the row.
+func (p *htmlPage) table(headers []string, rows [][]string, numFrom int, notes map[int]bool) {
+	p.b.WriteString("<table>\n<thead><tr>")
+	for i, h := range headers {
+		p.b.WriteString(htmlCell("th", h, i >= numFrom, ""))
+	}
+	p.b.WriteString("</tr></thead>\n<tbody>\n")
+	for r, row := range rows {
+		if notes[r] {
+			fmt.Fprintf(&p.b, "<tr class=\"note\"><td></td><td colspan=\"%d\">%s</td></tr>\n",
+				len(headers)-1, html.EscapeString(row[1]))
+			continue
+		}
+		p.b.WriteString("<tr>")
+		for i, cell := range row {
+			class := ""
+			if i == 0 {
+				class = "project"
+			}
+			p.b.WriteString(htmlCell("td", cell, i >= numFrom, class))
+		}
+		p.b.WriteString("</tr>\n")
+	}
+	p.b.WriteString("</tbody>\n</table>\n")
+}
+
+func htmlCell(tag, text string, numeric bool, class string) string {
+	if numeric {
+		class = strings.TrimSpace(class + " num")
+	}
+	if class != "" {
+		return fmt.Sprintf("<%s class=\"%s\">%s</%s>", tag, class, html.EscapeString(text), tag)
+	}
+	return fmt.Sprintf("<%s>%s</%s>", tag, html.EscapeString(text), tag)
+}
+
+// categories adds a task table for each configured category, like
+// writeColorCategoryTable.
+func (p *htmlPage) categories(tasks []model.TaskSummary, opts Options) {
+	categorized := groupTasksByCategory(tasks, opts)
+	for _, category := range opts.Categories {
+		p.heading(categoryTitle(category))
+		tasks := categorized[category]
+		if len(tasks) == 0 {
+			p.empty("No entries found.")
+			continue
+		}
+
+		sorted := sortTasksByProject(tasks)
+		shown := roundTasks(sorted, opts)
+		billed := roundBilledTasks(sorted, opts)
+		headers := []string{"Project", "Task", "Time", "Sessions"}
+		if opts.ShowBilled {
+			headers = []string{"Project", "Task", "Tracked", "Billed", "Sessions"}
+		}
+		var rows [][]string
+		notes := make(map[int]bool)
+		for i, t := range sorted {
+			row := []string{projectName(t), t.Description, formatDuration(shown[i])}
+			if opts.ShowBilled {
+				row = append(row, formatDuration(billed[i]))
+			}
+			rows = append(rows, append(row, fmt.Sprintf("%d", t.Sessions)))
+			for _, note := range t.Annotations {
+				notes[len(rows)] = true
+				rows = append(rows, []string{"", "↳ " + note})
+			}
+		}
+		p.table(headers, rows, 2, notes)
+	}
+}
+
+// weeks adds the charts of a month or range report, then its weekly
+// category matrix.
+func (p *htmlPage) weeks(report model.MonthData, opts Options, empty string) {
+	p.charts(MonthCharts(report, opts))
+	if len(report.Weeks) == 0 {
+		p.empty(empty)
+		return
+	}
+	if headers, rows := weeklyCategoryMatrix(report.Weeks, opts); len(headers) > 0 {
+		p.table(headers, rows, 1, nil)
+	}
+}
+
+// DayReportHTML writes a single-day report as a self-contained HTML page.
+func DayReportHTML(file *os.File, report model.DayReport, opts Options) error {
+	p := newHTMLPage(fmt.Sprintf("Day %d", birthdayDayNumber(report.Date, opts.BirthdayMonth, opts.BirthdayDay)),
+		report.Date.Format("Monday, Jan 2, 2006"), opts.total(report.Total, report.Billed))
+	p.charts(DayCharts(report, opts))
+	if len(report.Tasks) == 0 {
+		p.empty("No entries found for this day.")
+	} else {
+		p.categories(report.Tasks, opts)
+	}
+	return p.write(file)
+}
+
+// WeekReportHTML writes a week report as a self-contained HTML page.
+func WeekReportHTML(file *os.File, week model.WeekData, opts Options) error {
+	p := newHTMLPage(fmt.Sprintf("Week %d", opts.weekNumber(week)),
+		fmt.Sprintf("%s → %s", week.Start.Format("Mon, Jan 2"), week.End.Format("Mon, Jan 2")),
+		opts.total(week.Total, week.Billed))
+	p.charts(WeekCharts(week, opts))
+	if len(week.Tasks) == 0 {
+		p.empty("No entries found for this week.")
+	} else {
+		p.categories(week.Tasks, opts)
+	}
+	return p.write(file)
+}
+
+// MonthReportHTML writes a month report as a self-contained HTML page.
+func MonthReportHTML(file *os.File, month model.MonthData, year int, opts Options) error {
+	p := newHTMLPage(fmt.Sprintf("%s %d", month.Month, year), "", opts.total(month.Total, month.Billed))
+	p.weeks(month, opts, "No entries found for this month.")
+	return p.write(file)
+}
+
+// RangeReportHTML writes a custom date-range report as a self-contained HTML
+// page; end is exclusive.
+func RangeReportHTML(file *os.File, report model.MonthData, start, end time.Time, opts Options) error {
+	p := newHTMLPage(fmt.Sprintf("%s → %s", start.Format("Jan 2, 2006"), end.AddDate(0, 0, -1).Format("Jan 2, 2006")),
+		"", opts.total(report.Total, report.Billed))
+	p.weeks(report, opts, "No entries found for this range.")
+	return p.write(file)
+}
@@ -0,0 +1,40 @@
+package render
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/build"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestWeekReportHTMLEscapes(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2026, time.September, d, h, 0, 0, 0, time.Local) }
+	entries := []timewarrior.Entry{
+		{Start: day(16, 9), End: day(16, 11), Description: `<img src=x onerror="alert(1)">`,
+			Tags: []string{"<script>", "project:a<b>", "client:R&D"}, Annotation: "</td><script>alert(2)</script>"},
+		{Start: day(17, 9), End: day(17, 10), Description: "Q&A", Tags: []string{"r&d", "project:x&y"}, Annotation: "a & b"},
+	}
+	categories := []string{"<script>", "r&d", "misc"}
+	week := build.WeekReport(entries, day(13, 0), day(20, 0), build.Options{Categories: categories, FallbackCategory: "misc"})
+	opts := Options{Categories: categories, Dimensions: []string{"client"}}
+
+	got := output(t, func(f *os.File) error { return WeekReportHTML(f, week, opts) })
+	if strings.Contains(strings.ToLower(got), "<script") {
+		t.Error("output contains a <script> element")
+	}
+	if strings.Contains(got, "<img") {
+		t.Error("output contains markup from a description")
+	}
+	for _, want := range []string{"&lt;script&gt;", "R&amp;D", "r&amp;d", "Q&amp;A", "a &amp; b", "a&lt;b&gt;", "&lt;img src=x onerror=&#34;alert(1)&#34;&gt;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+	if bare := regexp.MustCompile(`&[^a-zA-Z#]|&[a-zA-Z0-9#]*[^a-zA-Z0-9#;]`).FindString(got); bare != "" {
+		t.Errorf("output contains an unescaped ampersand: %q", bare)
+	}
+}
@@ -0,0 +1,276 @@
+package render
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// JSONSchemaVersion is the version of the JSON output schema. It changes only
+// when a field is removed, renamed or changes meaning; new fields may be
+// added within a version.
+const JSONSchemaVersion = 1
+
+// The JSON schema is declared with its own types rather than by serialising
+// the model, so the model can change without breaking consumers. Durations
+// are whole seconds as tracked, without display rounding; dates are
+// YYYY-MM-DD in local time, with inclusive ends.
+
+type jsonReport struct {
+	SchemaVersion int    `json:"schema_version"`
+	Report        string `json:"report"` // day, week, month or range
+	jsonPeriod
+	// Weeks breaks month and range reports down week by week.
+	Weeks []jsonPeriod `json:"weeks,omitempty"`
+}
+
+// jsonPeriod is the body shared by every report and by each week of a month
+// or range.
+type jsonPeriod struct {
+	Week          int                    `json:"week,omitempty"`
+	Start         string                 `json:"start"`
+	End           string                 `json:"end"`
+	TotalSeconds  int64                  `json:"total_seconds"`
+	BilledSeconds *int64                 `json:"billed_seconds,omitempty"`
+	Weekdays      []jsonWeekday          `json:"weekdays,omitempty"`
+	Projects      []jsonShare            `json:"projects"`
+	Categories    []jsonShare            `json:"categories"`
+	Dimensions    map[string][]jsonShare `json:"dimensions"`
+	Tasks         []jsonTask             `json:"tasks,omitempty"`
+	CategoryTasks []jsonCategory         `json:"category_tasks,omitempty"`
+}
+
+type jsonWeekday struct {
+	Weekday string `json:"weekday"`
+	Date    string `json:"date"`
+	Seconds int64  `json:"seconds"`
+}
+
+// jsonShare is one value's part of a total. Percentages of categories may
+// add up to more than 100 when an entry counts under several.
+type jsonShare struct {
+	Name    string  `json:"name"`
+	Seconds int64   `json:"seconds"`
+	Percent float64 `json:"percent"`
+}
+
+type jsonTask struct {
+	Project       string            `json:"project"`
+	Description   string            `json:"description"`
+	Seconds       int64             `json:"seconds"`
+	BilledSeconds *int64            `json:"billed_seconds,omitempty"`
+	Sessions      int               `json:"sessions"`
+	Tags          []string          `json:"tags"`
+	Dimensions    map[string]string `json:"dimensions"`
+	Annotations   []string          `json:"annotations"`
+	// Weekdays holds the task's seconds per weekday in week reports.
+	Weekdays map[string]int64 `json:"weekdays,omitempty"`
+}
+
+// jsonCategory is one of the report's category tables: its tasks with their
+// time in that category.
+type jsonCategory struct {
+	Category string     `json:"category"`
+	Seconds  int64      `json:"seconds"`
+	Tasks    []jsonTask `json:"tasks"`
+}
+
+// DayReportJSON writes a day report in the JSON schema.
+func DayReportJSON(file *os.File, report model.DayReport, opts Options) error {
+	date := report.Date.Format(time.DateOnly)
+	return writeJSON(file, jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Report:        "day",
+		jsonPeriod: jsonPeriod{
+			Start:         date,
+			End:           date,
+			TotalSeconds:  seconds(report.Total),
+			BilledSeconds: jsonBilled(report.Billed, opts),
+			Projects:      jsonShares(report.ByProject, report.Total),
+			Categories:    jsonShares(report.ByTag, report.Total),
+			Dimensions:    jsonDimensions(report.ByDimension, report.Total),
+			Tasks:         jsonTasks(report.Tasks, false, opts),
+			CategoryTasks: jsonCategories(report.Tasks, false, opts),
+		},
+	})
+}
+
+// WeekReportJSON writes a week report in the JSON schema. Its start and end
+// are the part of the week within [start, end), end exclusive; weekdays still
+// lists the whole week.
+func WeekReportJSON(file *os.File, week model.WeekData, start, end time.Time, opts Options) error {
+	period := jsonWeek(week, opts)
+	if start.After(week.Start) {
+		period.Start = start.Format(time.DateOnly)
+	}
+	if weekEnd := week.Start.AddDate(0, 0, 7); end.Before(weekEnd) {
+		period.End = end.Add(-time.Nanosecond).Format(time.DateOnly)
+	}
+	return writeJSON(file, jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Report:        "week",
+		jsonPeriod:    period,
+	})
+}
+
+// MonthReportJSON writes a month report in the JSON schema.
+func MonthReportJSON(file *os.File, month model.MonthData, year int, opts Options) error {
+	start := time.Date(year, month.Month, 1, 0, 0, 0, 0, time.Local)
+	return writeJSON(file, jsonWeeks("month", month, start, start.AddDate(0, 1, 0), opts))
+}
+
+// RangeReportJSON writes a range report in the JSON schema; end is exclusive.
+func RangeReportJSON(file *os.File, report model.MonthData, start, end time.Time, opts Options) error {
+	return writeJSON(file, jsonWeeks("range", report, start, end, opts))
+}
+
+func writeJSON(file *os.File, report jsonReport) error {
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// jsonWeeks builds a month or range report: the totals of the whole period
+// followed by each week.
+func jsonWeeks(kind string, report model.MonthData, start, end time.Time, opts Options) jsonReport {
+	tags, projects := aggregateWeeks(report.Weeks)
+	out := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Report:        kind,
+		jsonPeriod: jsonPeriod{
+			Start:         start.Format(time.DateOnly),
+			End:           end.Add(-time.Nanosecond).Format(time.DateOnly),
+			TotalSeconds:  seconds(report.Total),
+			BilledSeconds: jsonBilled(report.Billed, opts),
+			Projects:      jsonShares(projects, report.Total),
+			Categories:    jsonShares(tags, report.Total),
+			Dimensions:    jsonDimensions(aggregateWeekDimensions(report.Weeks), report.Total),
+		},
+		Weeks: []jsonPeriod{},
+	}
+	for _, week := range report.Weeks {
+		out.Weeks = append(out.Weeks, jsonWeek(week, opts))
+	}
+	return out
+}
+
+func jsonWeek(week model.WeekData, opts Options) jsonPeriod {
+	period := jsonPeriod{
+		Week:          opts.weekNumber(week),
+		Start:         week.Start.Format(time.DateOnly),
+		End:           week.End.Format(time.DateOnly),
+		TotalSeconds:  seconds(week.Total),
+		BilledSeconds: jsonBilled(week.Billed, opts),
+		Projects:      jsonShares(week.ByProject, week.Total),
+		Categories:    jsonShares(week.ByTag, week.Total),
+		Dimensions:    jsonDimensions(week.ByDimension, week.Total),
+		Tasks:         jsonTasks(week.Tasks, true, opts),
+		CategoryTasks: jsonCategories(week.Tasks, true, opts),
+	}
+	dayTotals := make(map[time.Weekday]time.Duration)
+	for _, t := range week.Tasks {
+		for day, d := range t.DayTotals {
+			dayTotals[day] += d
+		}
+	}
+	for i, day := range weekdaysFrom(week.Start.Weekday()) {
+		period.Weekdays = append(period.Weekdays, jsonWeekday{
+			Weekday: day.String(),
+			Date:    week.Start.AddDate(0, 0, i).Format(time.DateOnly),
+			Seconds: seconds(dayTotals[day]),
+		})
+	}
+	return period
+}
+
+func jsonTasks(tasks []model.TaskSummary, weekdays bool, opts Options) []jsonTask {
+	out := []jsonTask{}
+	for _, t := range sortTasksByProject(tasks) {
+		task := jsonTask{
+			Project:       t.Project,
+			Description:   t.Description,
+			Seconds:       seconds(t.TotalTime),
+			BilledSeconds: jsonBilled(t.BilledTime, opts),
+			Sessions:      t.Sessions,
+			Tags:          []string{},
+			Dimensions:    t.Dimensions,
+			Annotations:   t.Annotations,
+		}
+		for tag := range t.Tags {
+			task.Tags = append(task.Tags, tag)
+		}
+		sort.Strings(task.Tags)
+		if task.Dimensions == nil {
+			task.Dimensions = map[string]string{}
+		}
+		if task.Annotations == nil {
+			task.Annotations = []string{}
+		}
+		if weekdays {
+			task.Weekdays = make(map[string]int64)
+			for day, d := range t.DayTotals {
+				task.Weekdays[day.String()] = seconds(d)
+			}
+		}
+		out = append(out, task)
+	}
+	return out
+}
+
+func jsonCategories(tasks []model.TaskSummary, weekdays bool, opts Options) []jsonCategory {
+	categorized := groupTasksByCategory(tasks, opts)
+	var out []jsonCategory
+	for _, category := range opts.Categories {
+		c := jsonCategory{Category: category, Tasks: jsonTasks(categorized[category], weekdays, opts)}
+		for _, t := range categorized[category] {
+			c.Seconds += seconds(t.TotalTime)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// jsonShares lists values by time descending, then name.
+func jsonShares(values map[string]time.Duration, total time.Duration) []jsonShare {
+	out := []jsonShare{}
+	for name, d := range values {
+		out = append(out, jsonShare{
+			Name:    name,
+			Seconds: seconds(d),
+			Percent: math.Round(sharePercent(d, total)*10) / 10,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Seconds != out[j].Seconds {
+			return out[i].Seconds > out[j].Seconds
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func jsonDimensions(byDimension map[string]map[string]time.Duration, total time.Duration) map[string][]jsonShare {
+	out := make(map[string][]jsonShare, len(byDimension))
+	for dimension, values := range byDimension {
+		out[dimension] = jsonShares(values, total)
+	}
+	return out
+}
+
+// jsonBilled returns billed seconds when a billing policy is configured, and
+// nil to leave the field out otherwise.
+func jsonBilled(d time.Duration, opts Options) *int64 {
+	if !opts.ShowBilled {
+		return nil
+	}
+	s := seconds(d)
+	return &s
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/build"
+	"github.com/amiraminb/lume/internal/report/model"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+// output runs write against a temporary file and returns what it wrote.
+func output(t *testing.T, write func(*os.File) error) string {
+	t.Helper()
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := write(file); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// sampleWeek is a week report of a few tasks, limited to Wednesday to
+// Friday, shared by the renderer tests.
+func sampleWeek() (week model.WeekData, from, to time.Time, opts Options) {
+	day := func(d, h, m int) time.Time { return time.Date(2026, time.September, d, h, m, 0, 0, time.Local) }
+	entries := []timewarrior.Entry{
+		{Start: day(14, 9, 0), End: day(14, 10, 0), Description: "before the range", Tags: []string{"dev", "project:acme"}},
+		{Start: day(16, 9, 0), End: day(16, 10, 30), Description: "fix login", Tags: []string{"dev", "project:acme.api", "client:acme"}, Annotation: "see #42"},
+		{Start: day(17, 14, 0), End: day(17, 14, 45), Description: "standup", Tags: []string{"meetings", "dev"}},
+		{Start: day(17, 23, 30), End: day(18, 0, 30), Description: "fix login", Tags: []string{"dev", "project:acme.api", "client:acme"}},
+		{Start: day(19, 9, 0), End: day(19, 10, 0), Description: "after the range", Tags: []string{"dev"}},
+	}
+	categories := []string{"dev", "meetings", "misc"}
+	from, to = day(16, 0, 0), day(19, 0, 0)
+	week = build.WeekReport(entries, from, to, build.Options{
+		WeekStart: time.Sunday, ISOWeeks: true, Categories: categories, FallbackCategory: "misc", Allocation: build.AllocateSplit,
+	})
+	return week, from, to, Options{ISOWeeks: true, Categories: categories, WeekStart: time.Sunday}
+}
+
+func TestWeekReportJSON(t *testing.T) {
+	week, from, to, opts := sampleWeek()
+	got := output(t, func(f *os.File) error { return WeekReportJSON(f, week, from, to, opts) })
+	want, err := os.ReadFile(filepath.Join("testdata", "week.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("WeekReportJSON() =\n%s\nwant testdata/week.json:\n%s", got, want)
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal([]byte(got), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Start != "2026-09-16" || report.End != "2026-09-18" {
+		t.Errorf("start, end = %s, %s, want the range 2026-09-16, 2026-09-18", report.Start, report.End)
+	}
+	if report.TotalSeconds != 3*3600+15*60 {
+		t.Errorf("total_seconds = %d, want %d", report.TotalSeconds, 3*3600+15*60)
+	}
+	if len(report.Weekdays) != 7 || report.Weekdays[0].Date != "2026-09-13" {
+		t.Errorf("weekdays = %+v, want the whole week from 2026-09-13", report.Weekdays)
+	}
+}
@@ -6,89 +6,107 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/amiraminb/lume/internal/report/civil"
 	"github.com/amiraminb/lume/internal/report/model"
 )
 
-func YearIndex(file *os.File, report model.YearReport) {
+func YearIndex(file *os.File, report model.YearReport, opts Options) {
 	fmt.Fprintf(file, "# Time Report %d\n\n", report.Year)
-	fmt.Fprintf(file, "> **Total Tracked:** %s\n\n", formatDuration(report.Total))
+	fmt.Fprintf(file, "> **Total Tracked:** %s\n\n", opts.total(report.Total, report.Billed))
 
-	yearTags := make(map[string]float64)
-	yearProjects := make(map[string]float64)
+	yearTags := make(map[string]time.Duration)
+	yearProjects := make(map[string]time.Duration)
 	for _, month := range report.Months {
 		for _, week := range month.Weeks {
-			for tag, hours := range week.ByTag {
-				yearTags[tag] += hours
+			for tag, d := range week.ByTag {
+				yearTags[tag] += d
 			}
-			for project, hours := range week.ByProject {
-				yearProjects[project] += hours
+			for project, d := range week.ByProject {
+				yearProjects[project] += d
 			}
 		}
 	}
 
 	if len(yearProjects) > 0 {
-		writeProjectSummary(file, yearProjects, report.Total)
+		writeProjectSummary(file, yearProjects, report.Total, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
+	var yearWeeks []model.WeekData
+	for _, month := range report.Months {
+		yearWeeks = append(yearWeeks, month.Weeks...)
+	}
+	writeDimensionSummaries(file, aggregateWeekDimensions(yearWeeks), report.Total, opts)
+
 	if len(yearTags) > 0 {
-		writeTagSummary(file, yearTags, report.Total)
+		writeTagSummary(file, yearTags, report.Total, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
 	fmt.Fprintf(file, "---\n\n")
 	fmt.Fprintf(file, "## Months\n\n")
 
-	for _, month := range report.Months {
+	monthTotals := make([]time.Duration, len(report.Months))
+	for i, month := range report.Months {
+		monthTotals[i] = month.Total
+	}
+	shown := opts.Rounding.roundRows(monthTotals, report.Total)
+	for i, month := range report.Months {
 		monthFile := fmt.Sprintf("%02d-%s.md", month.Month, strings.ToLower(month.Month.String()))
-		fmt.Fprintf(file, "- [%s](%s) — %s\n", month.Month.String(), monthFile, formatDuration(month.Total))
+		fmt.Fprintf(file, "- [%s](%s) — %s\n", month.Month.String(), monthFile, formatDuration(shown[i]))
 	}
 }
 
-func MonthFile(file *os.File, month model.MonthData, year int, birthdayMonth time.Month, birthdayDay int) {
+func MonthFile(file *os.File, month model.MonthData, year int, opts Options) {
 	fmt.Fprintf(file, "# %s %d\n\n", month.Month.String(), year)
-	fmt.Fprintf(file, "> **Monthly Total:** %s\n\n", formatDuration(month.Total))
+	fmt.Fprintf(file, "> **Monthly Total:** %s\n\n", opts.total(month.Total, month.Billed))
 	fmt.Fprintf(file, "---\n\n")
 
-	monthTags := make(map[string]float64)
-	monthProjects := make(map[string]float64)
+	monthTags := make(map[string]time.Duration)
+	monthProjects := make(map[string]time.Duration)
 	for _, week := range month.Weeks {
-		for tag, hours := range week.ByTag {
-			monthTags[tag] += hours
+		for tag, d := range week.ByTag {
+			monthTags[tag] += d
 		}
-		for project, hours := range week.ByProject {
-			monthProjects[project] += hours
+		for project, d := range week.ByProject {
+			monthProjects[project] += d
 		}
 	}
 
 	if len(monthProjects) > 0 {
-		writeProjectSummary(file, monthProjects, month.Total)
+		writeProjectSummary(file, monthProjects, month.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
+	writeDimensionSummaries(file, aggregateWeekDimensions(month.Weeks), month.Total, opts)
+
 	if len(monthTags) > 0 {
-		writeTagSummary(file, monthTags, month.Total)
+		writeTagSummary(file, monthTags, month.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
 	for _, week := range month.Weeks {
-		WeekSection(file, week, birthdayMonth, birthdayDay)
+		WeekSection(file, week, opts)
 	}
 }
 
-func DayReport(file *os.File, report model.DayReport, birthdayMonth time.Month, birthdayDay int) {
-	fmt.Fprintf(file, "# Day %d\n", birthdayDayNumber(report.Date, birthdayMonth, birthdayDay))
+func DayReport(file *os.File, report model.DayReport, opts Options) {
+	fmt.Fprintf(file, "# Day %d\n", birthdayDayNumber(report.Date, opts.BirthdayMonth, opts.BirthdayDay))
 	fmt.Fprintf(file, "> %s\n\n", report.Date.Format("Monday, Jan 2, 2006"))
-	fmt.Fprintf(file, "> **Daily Total:** %s\n\n", formatDuration(report.Total))
+	fmt.Fprintf(file, "> **Daily Total:** %s\n\n", opts.total(report.Total, report.Billed))
 
 	if len(report.ByProject) > 0 {
-		writeProjectSummary(file, report.ByProject, report.Total)
+		writeProjectSummary(file, report.ByProject, report.Total, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
+	writeDimensionSummaries(file, report.ByDimension, report.Total, opts)
+
 	if len(report.ByTag) > 0 {
-		writeTagSummary(file, report.ByTag, report.Total)
+		writeTagSummary(file, report.ByTag, report.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
@@ -97,31 +115,32 @@ func DayReport(file *os.File, report model.DayReport, birthdayMonth time.Month,
 		return
 	}
 
-	categorized := groupTasksByCategory(report.Tasks)
-	writeCategoryTable(file, "Dev", categorized[categoryDev])
-	writeCategoryTable(file, "Meetings", categorized[categoryMeetings])
-	writeCategoryTable(file, "Knowledge", categorized[categoryKnowledge])
-	writeCategoryTable(file, "Misc", categorized[categoryMisc])
+	categorized := groupTasksByCategory(report.Tasks, opts)
+	for _, category := range opts.Categories {
+		writeCategoryTable(file, categoryTitle(category), categorized[category], opts)
+	}
 }
 
-func WeekReport(file *os.File, week model.WeekData, birthdayMonth time.Month, birthdayDay int) {
-	fmt.Fprintf(file, "# Week %d\n", birthdayWeekNumber(week.Start, birthdayMonth, birthdayDay))
+func WeekReport(file *os.File, week model.WeekData, opts Options) {
+	fmt.Fprintf(file, "# Week %d\n", opts.weekNumber(week))
 	fmt.Fprintf(file, "> %s → %s\n\n",
 		week.Start.Format("Mon, Jan 2"),
 		week.End.Format("Mon, Jan 2"))
 
-	fmt.Fprintf(file, "**Total:** %s\n\n", formatDuration(week.Total))
+	fmt.Fprintf(file, "**Total:** %s\n\n", opts.total(week.Total, week.Billed))
 
-	writeWeekdayChart(file, week)
+	writeWeekdayChart(file, week, opts)
 	fmt.Fprintf(file, "\n")
 
 	if len(week.ByProject) > 0 {
-		writeShareChart(file, "Projects", week.ByProject, week.Total)
+		writeProjectShareChart(file, week.ByProject, week.Total, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
+	writeDimensionCharts(file, week.ByDimension, week.Total, opts)
+
 	if len(week.ByTag) > 0 {
-		writeShareChart(file, "Categories", week.ByTag, week.Total)
+		writeShareChart(file, "Categories", week.ByTag, week.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
@@ -130,41 +149,42 @@ func WeekReport(file *os.File, week model.WeekData, birthdayMonth time.Month, bi
 		return
 	}
 
-	categorized := groupTasksByCategory(week.Tasks)
-	writeCategoryWeekTable(file, "Dev", categorized[categoryDev])
-	writeCategoryWeekTable(file, "Meetings", categorized[categoryMeetings])
-	writeCategoryWeekTable(file, "Knowledge", categorized[categoryKnowledge])
-	writeCategoryWeekTable(file, "Misc", categorized[categoryMisc])
+	categorized := groupTasksByCategory(week.Tasks, opts)
+	for _, category := range opts.Categories {
+		writeCategoryWeekTable(file, categoryTitle(category), categorized[category], week.Start.Weekday(), opts)
+	}
 }
 
-func MonthReport(file *os.File, month model.MonthData, year int, birthdayMonth time.Month, birthdayDay int) {
+func MonthReport(file *os.File, month model.MonthData, year int, opts Options) {
 	fmt.Fprintf(file, "# %s %d\n\n", month.Month.String(), year)
-	fmt.Fprintf(file, "> **Monthly Total:** %s\n\n", formatDuration(month.Total))
+	fmt.Fprintf(file, "> **Monthly Total:** %s\n\n", opts.total(month.Total, month.Billed))
 	fmt.Fprintf(file, "---\n\n")
 
-	monthTags := make(map[string]float64)
-	monthProjects := make(map[string]float64)
+	monthTags := make(map[string]time.Duration)
+	monthProjects := make(map[string]time.Duration)
 	for _, week := range month.Weeks {
-		for tag, hours := range week.ByTag {
-			monthTags[tag] += hours
+		for tag, d := range week.ByTag {
+			monthTags[tag] += d
 		}
-		for project, hours := range week.ByProject {
-			monthProjects[project] += hours
+		for project, d := range week.ByProject {
+			monthProjects[project] += d
 		}
 	}
 
 	if len(month.Weeks) > 0 {
-		writeWeekTrend(file, month.Weeks, birthdayMonth, birthdayDay)
+		writeWeekTrend(file, month.Weeks, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
 	if len(monthProjects) > 0 {
-		writeShareChart(file, "Projects", monthProjects, month.Total)
+		writeProjectShareChart(file, monthProjects, month.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
+	writeDimensionCharts(file, aggregateWeekDimensions(month.Weeks), month.Total, opts)
+
 	if len(monthTags) > 0 {
-		writeShareChart(file, "Categories", monthTags, month.Total)
+		writeShareChart(file, "Categories", monthTags, month.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
@@ -174,38 +194,40 @@ func MonthReport(file *os.File, month model.MonthData, year int, birthdayMonth t
 	}
 
 	for _, week := range month.Weeks {
-		WeekSection(file, week, birthdayMonth, birthdayDay)
+		WeekSection(file, week, opts)
 	}
 }
 
-func RangeReport(file *os.File, report model.MonthData, start time.Time, end time.Time, birthdayMonth time.Month, birthdayDay int) {
+func RangeReport(file *os.File, report model.MonthData, start time.Time, end time.Time, opts Options) {
 	fmt.Fprintf(file, "# %s → %s\n\n", start.Format("Jan 2, 2006"), end.AddDate(0, 0, -1).Format("Jan 2, 2006"))
-	fmt.Fprintf(file, "> **Range Total:** %s\n\n", formatDuration(report.Total))
+	fmt.Fprintf(file, "> **Range Total:** %s\n\n", opts.total(report.Total, report.Billed))
 	fmt.Fprintf(file, "---\n\n")
 
-	rangeTags := make(map[string]float64)
-	rangeProjects := make(map[string]float64)
+	rangeTags := make(map[string]time.Duration)
+	rangeProjects := make(map[string]time.Duration)
 	for _, week := range report.Weeks {
-		for tag, hours := range week.ByTag {
-			rangeTags[tag] += hours
+		for tag, d := range week.ByTag {
+			rangeTags[tag] += d
 		}
-		for project, hours := range week.ByProject {
-			rangeProjects[project] += hours
+		for project, d := range week.ByProject {
+			rangeProjects[project] += d
 		}
 	}
 
 	if len(report.Weeks) > 0 {
-		writeWeekTrend(file, report.Weeks, birthdayMonth, birthdayDay)
+		writeWeekTrend(file, report.Weeks, opts)
 		fmt.Fprintf(file, "\n")
 	}
 
 	if len(rangeProjects) > 0 {
-		writeShareChart(file, "Projects", rangeProjects, report.Total)
+		writeProjectShareChart(file, rangeProjects, report.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
+	writeDimensionCharts(file, aggregateWeekDimensions(report.Weeks), report.Total, opts)
+
 	if len(rangeTags) > 0 {
-		writeShareChart(file, "Categories", rangeTags, report.Total)
+		writeShareChart(file, "Categories", rangeTags, report.Total, opts)
 		fmt.Fprintf(file, "\n---\n\n")
 	}
 
@@ -215,194 +237,131 @@ func RangeReport(file *os.File, report model.MonthData, start time.Time, end tim
 	}
 
 	for _, week := range report.Weeks {
-		WeekSection(file, week, birthdayMonth, birthdayDay)
+		WeekSection(file, week, opts)
 	}
 }
 
-func WeekSection(file *os.File, week model.WeekData, birthdayMonth time.Month, birthdayDay int) {
-	fmt.Fprintf(file, "## Week %d\n", birthdayWeekNumber(week.Start, birthdayMonth, birthdayDay))
-	fmt.Fprintf(file, "> %s → %s\n\n",
-		week.Start.Format("Mon, Jan 2"),
-		week.End.Format("Mon, Jan 2"))
-
-	fmt.Fprintf(file, "**Total:** %s\n\n", formatDuration(week.Total))
-
-	writeWeekdayChart(file, week)
-	fmt.Fprintf(file, "\n")
+// PivotReport renders a pivot as a Markdown table with a closing Total row.
+func PivotReport(file *os.File, pivot model.Pivot, opts Options) {
+	fmt.Fprintf(file, "# %s\n\n", pivotTitle(pivot))
+	fmt.Fprintf(file, "> **Total:** %s\n\n", opts.duration(pivot.Total))
 
-	if len(week.ByProject) > 0 {
-		writeShareChart(file, "Projects", week.ByProject, week.Total)
-		fmt.Fprintf(file, "\n")
+	if len(pivot.Rows) == 0 {
+		fmt.Fprintf(file, "No entries found.\n")
+		return
 	}
 
-	if len(week.ByTag) > 0 {
-		writeShareChart(file, "Categories", week.ByTag, week.Total)
-		fmt.Fprintf(file, "\n---\n\n")
+	headers, rows := pivotTable(pivot, "—", opts)
+	escape := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = strings.ReplaceAll(c, "|", "\\|")
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
 	}
 
-	if len(week.Tasks) > 0 {
-		categorized := groupTasksByCategory(week.Tasks)
-		writeCategoryTable(file, "Dev", categorized[categoryDev])
-		writeCategoryTable(file, "Meetings", categorized[categoryMeetings])
-		writeCategoryTable(file, "Knowledge", categorized[categoryKnowledge])
-		writeCategoryTable(file, "Misc", categorized[categoryMisc])
+	fmt.Fprintln(file, escape(headers))
+	align := make([]string, len(headers))
+	align[0] = ":---"
+	for i := 1; i < len(align); i++ {
+		align[i] = "---:"
 	}
-
-	fmt.Fprintf(file, "---\n\n")
-}
-
-func writeWeekTasks(file *os.File, tasks []model.TaskSummary) {
-	tasksByTag := groupTasksByTag(tasks)
-
-	var tags []string
-	for tag := range tasksByTag {
-		tags = append(tags, tag)
+	fmt.Fprintln(file, "|"+strings.Join(align, "|")+"|")
+	for _, row := range rows {
+		fmt.Fprintln(file, escape(row))
 	}
-	sort.Slice(tags, func(i, j int) bool {
-		var totalI, totalJ float64
-		for _, groupedTasks := range tasksByTag[tags[i]] {
-			totalI += sumTaskHours(groupedTasks)
-		}
-		for _, groupedTasks := range tasksByTag[tags[j]] {
-			totalJ += sumTaskHours(groupedTasks)
-		}
-		return totalI > totalJ
-	})
+}
 
-	for _, tag := range tags {
-		projectGroups := tasksByTag[tag]
-		var tagTotal float64
-		for _, groupedTasks := range projectGroups {
-			for _, t := range groupedTasks {
-				tagTotal += t.TotalTime
+// BillingReport writes a billing report as a client → project → task table
+// followed by the subtotal, tax lines, total and invoiced split.
+func BillingReport(file *os.File, report model.BillingReport, opts Options) {
+	fmt.Fprintf(file, "# %s\n\n", billingTitle(report))
+	fmt.Fprintf(file, "> **Total:** %s\n\n", formatAmount(report.Total, report.Currency))
+
+	if len(report.Clients) == 0 {
+		fmt.Fprintf(file, "No billable entries found.\n\n")
+	} else {
+		headers, rows, levels := billingTable(report, "\u00a0\u00a0", opts)
+		fmt.Fprintf(file, "| %s |\n", strings.Join(headers, " | "))
+		fmt.Fprintf(file, "|:---|%s\n", strings.Repeat("---:|", len(headers)-1))
+		for i, row := range rows {
+			cells := make([]string, len(row))
+			for j, c := range row {
+				cells[j] = strings.ReplaceAll(c, "|", "\\|")
+				if levels[i] == billingClient && c != "" {
+					cells[j] = "**" + cells[j] + "**"
+				}
 			}
+			fmt.Fprintf(file, "| %s |\n", strings.Join(cells, " | "))
 		}
+		fmt.Fprintf(file, "\n")
+	}
 
-		fmt.Fprintf(file, "### %s\n", tag)
-		fmt.Fprintf(file, "**Subtotal:** %s\n\n", formatDuration(tagTotal))
-
-		projects := sortedProjects(projectGroups)
-		for _, project := range projects {
-			projectTasks := projectGroups[project]
-			projectTotal := sumTaskHours(projectTasks)
-
-			fmt.Fprintf(file, "#### project:%s\n", project)
-			fmt.Fprintf(file, "**Project Subtotal:** %s\n\n", formatDuration(projectTotal))
-
-			fmt.Fprintf(file, "| Task | Time | Sessions |\n")
-			fmt.Fprintf(file, "|:-----|-----:|---------:|\n")
-
-			for _, t := range projectTasks {
-				fmt.Fprintf(file, "| %s | %s | %d |\n",
-					truncate(t.Description, 55),
-					formatDuration(t.TotalTime),
-					t.Sessions)
-			}
-			fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "| | |\n|:---|---:|\n")
+	for _, line := range billingSummary(report, opts) {
+		if line[0] == "Total" {
+			fmt.Fprintf(file, "| **%s** | **%s** |\n", line[0], line[1])
+			continue
 		}
+		fmt.Fprintf(file, "| %s | %s |\n", line[0], line[1])
 	}
+	fmt.Fprintf(file, "\n")
 }
 
-func groupTasksByTag(tasks []model.TaskSummary) map[string]map[string][]model.TaskSummary {
-	grouped := make(map[string]map[string][]model.TaskSummary)
+func WeekSection(file *os.File, week model.WeekData, opts Options) {
+	fmt.Fprintf(file, "## Week %d\n", opts.weekNumber(week))
+	fmt.Fprintf(file, "> %s → %s\n\n",
+		week.Start.Format("Mon, Jan 2"),
+		week.End.Format("Mon, Jan 2"))
 
-	for _, t := range tasks {
-		tags := taskNonProjectTags(t)
-		project := t.Project
-		if project == "" {
-			project = "unknown"
-		}
+	fmt.Fprintf(file, "**Total:** %s\n\n", opts.total(week.Total, week.Billed))
 
-		for _, tag := range tags {
-			if _, ok := grouped[tag]; !ok {
-				grouped[tag] = make(map[string][]model.TaskSummary)
-			}
-			grouped[tag][project] = append(grouped[tag][project], t)
-		}
-	}
+	writeWeekdayChart(file, week, opts)
+	fmt.Fprintf(file, "\n")
 
-	for tag := range grouped {
-		for project := range grouped[tag] {
-			sort.Slice(grouped[tag][project], func(i, j int) bool {
-				return grouped[tag][project][i].TotalTime > grouped[tag][project][j].TotalTime
-			})
-		}
+	if len(week.ByProject) > 0 {
+		writeProjectShareChart(file, week.ByProject, week.Total, opts)
+		fmt.Fprintf(file, "\n")
 	}
 
-	return grouped
-}
+	writeDimensionCharts(file, week.ByDimension, week.Total, opts)
 
-func taskNonProjectTags(task model.TaskSummary) []string {
-	var tags []string
-	for tag := range task.Tags {
-		if strings.HasPrefix(tag, "project:") {
-			continue
-		}
-		tags = append(tags, tag)
-	}
-	if len(tags) == 0 {
-		return []string{"untagged"}
+	if len(week.ByTag) > 0 {
+		writeShareChart(file, "Categories", week.ByTag, week.Total, opts)
+		fmt.Fprintf(file, "\n---\n\n")
 	}
-	sort.Strings(tags)
-	return tags
-}
 
-func sortedProjects(projectGroups map[string][]model.TaskSummary) []string {
-	var projects []string
-	for project := range projectGroups {
-		projects = append(projects, project)
+	if len(week.Tasks) > 0 {
+		categorized := groupTasksByCategory(week.Tasks, opts)
+		for _, category := range opts.Categories {
+			writeCategoryTable(file, categoryTitle(category), categorized[category], opts)
+		}
 	}
-	sort.Slice(projects, func(i, j int) bool {
-		return sumTaskHours(projectGroups[projects[i]]) > sumTaskHours(projectGroups[projects[j]])
-	})
-	return projects
-}
 
-func sumTaskHours(tasks []model.TaskSummary) float64 {
-	var total float64
-	for _, t := range tasks {
-		total += t.TotalTime
-	}
-	return total
+	fmt.Fprintf(file, "---\n\n")
 }
 
-type taskCategory string
-
-const (
-	categoryDev       taskCategory = "dev"
-	categoryMeetings  taskCategory = "meetings"
-	categoryKnowledge taskCategory = "knowledge"
-	categoryMisc      taskCategory = "misc"
-)
-
-func groupTasksByCategory(tasks []model.TaskSummary) map[taskCategory][]model.TaskSummary {
-	categorized := map[taskCategory][]model.TaskSummary{
-		categoryDev:       {},
-		categoryMeetings:  {},
-		categoryKnowledge: {},
-		categoryMisc:      {},
+// groupTasksByCategory files each task under the configured categories it
+// has time in (TaskSummary.Categories, already allocated by build). A task
+// that only partly belongs to a category appears there with the time,
+// sessions and days of that part.
+func groupTasksByCategory(tasks []model.TaskSummary, opts Options) map[string][]model.TaskSummary {
+	categorized := make(map[string][]model.TaskSummary, len(opts.Categories))
+	for _, category := range opts.Categories {
+		categorized[category] = []model.TaskSummary{}
 	}
 
 	for _, task := range tasks {
-		matched := false
-		for tag := range task.Tags {
-			switch strings.ToLower(tag) {
-			case string(categoryDev):
-				categorized[categoryDev] = append(categorized[categoryDev], task)
-				matched = true
-			case string(categoryMeetings):
-				categorized[categoryMeetings] = append(categorized[categoryMeetings], task)
-				matched = true
-			case string(categoryKnowledge):
-				categorized[categoryKnowledge] = append(categorized[categoryKnowledge], task)
-				matched = true
-			case string(categoryMisc):
-				categorized[categoryMisc] = append(categorized[categoryMisc], task)
-				matched = true
+		for category, part := range task.Categories {
+			if _, ok := categorized[category]; !ok || part.Total <= 0 {
+				continue
 			}
-		}
-		if !matched {
-			categorized[categoryMisc] = append(categorized[categoryMisc], task)
+			t := task
+			t.TotalTime = part.Total
+			t.BilledTime = part.Billed
+			t.Sessions = part.Sessions
+			t.DayTotals = part.DayTotals
+			categorized[category] = append(categorized[category], t)
 		}
 	}
 
@@ -415,7 +374,13 @@ func groupTasksByCategory(tasks []model.TaskSummary) map[taskCategory][]model.Ta
 	return categorized
 }
 
-func writeCategoryTable(file *os.File, title string, tasks []model.TaskSummary) {
+// categoryTitle turns a category name into a table heading ("dev" -> "Dev").
+func categoryTitle(category string) string {
+	r, size := utf8.DecodeRuneInString(category)
+	return string(unicode.ToUpper(r)) + category[size:]
+}
+
+func writeCategoryTable(file *os.File, title string, tasks []model.TaskSummary, opts Options) {
 	fmt.Fprintf(file, "## %s\n\n", title)
 
 	if len(tasks) == 0 {
@@ -425,19 +390,39 @@ func writeCategoryTable(file *os.File, title string, tasks []model.TaskSummary)
 
 	sorted := sortTasksByProject(tasks)
 
+	shown := roundTasks(sorted, opts)
+
+	if opts.ShowBilled {
+		billed := roundBilledTasks(sorted, opts)
+		fmt.Fprintf(file, "| Project | Task | Tracked | Billed | Sessions |\n")
+		fmt.Fprintf(file, "|:--------|:-----|--------:|-------:|---------:|\n")
+		for i, t := range sorted {
+			fmt.Fprintf(file, "| %s | %s | %s | %s | %d |\n",
+				truncate(projectName(t), 24),
+				truncate(t.Description, 55),
+				formatDuration(shown[i]),
+				formatDuration(billed[i]),
+				t.Sessions)
+			writeAnnotationRows(file, t, 5)
+		}
+		fmt.Fprintf(file, "\n")
+		return
+	}
+
 	fmt.Fprintf(file, "| Project | Task | Time | Sessions |\n")
 	fmt.Fprintf(file, "|:--------|:-----|-----:|---------:|\n")
-	for _, t := range sorted {
+	for i, t := range sorted {
 		fmt.Fprintf(file, "| %s | %s | %s | %d |\n",
 			truncate(projectName(t), 24),
 			truncate(t.Description, 55),
-			formatDuration(t.TotalTime),
+			formatDuration(shown[i]),
 			t.Sessions)
+		writeAnnotationRows(file, t, 4)
 	}
 	fmt.Fprintf(file, "\n")
 }
 
-func writeCategoryWeekTable(file *os.File, title string, tasks []model.TaskSummary) {
+func writeCategoryWeekTable(file *os.File, title string, tasks []model.TaskSummary, weekStart time.Weekday, opts Options) {
 	fmt.Fprintf(file, "## %s\n\n", title)
 
 	if len(tasks) == 0 {
@@ -447,30 +432,76 @@ func writeCategoryWeekTable(file *os.File, title string, tasks []model.TaskSumma
 
 	sorted := sortTasksByProject(tasks)
 
-	fmt.Fprintf(file, "| Project | Task | Time | Sun | Mon | Tue | Wed | Thu | Fri | Sat |\n")
-	fmt.Fprintf(file, "|:--------|:-----|-----:|----:|----:|----:|----:|----:|----:|----:|\n")
-	for _, t := range sorted {
-		fmt.Fprintf(file, "| %s | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+	days := weekdaysFrom(weekStart)
+
+	timeColumns, timeAlign := " Time |", "-----:|"
+	if opts.ShowBilled {
+		timeColumns, timeAlign = " Tracked | Billed |", "--------:|-------:|"
+	}
+	fmt.Fprintf(file, "| Project | Task |%s", timeColumns)
+	for _, day := range days {
+		fmt.Fprintf(file, " %s |", day.String()[:3])
+	}
+	fmt.Fprintf(file, "\n|:--------|:-----|%s%s\n", timeAlign, strings.Repeat("----:|", len(days)))
+	shown := roundTasks(sorted, opts)
+	billed := roundBilledTasks(sorted, opts)
+	for i, t := range sorted {
+		fmt.Fprintf(file, "| %s | %s | %s |",
 			truncate(projectName(t), 24),
 			truncate(t.Description, 55),
-			formatDuration(t.TotalTime),
-			formatDayHours(t, time.Sunday),
-			formatDayHours(t, time.Monday),
-			formatDayHours(t, time.Tuesday),
-			formatDayHours(t, time.Wednesday),
-			formatDayHours(t, time.Thursday),
-			formatDayHours(t, time.Friday),
-			formatDayHours(t, time.Saturday))
+			formatDuration(shown[i]))
+		if opts.ShowBilled {
+			fmt.Fprintf(file, " %s |", formatDuration(billed[i]))
+		}
+		dayTimes := make([]time.Duration, len(days))
+		for j, day := range days {
+			dayTimes[j] = t.DayTotals[day]
+		}
+		for _, d := range opts.Rounding.roundRowsTo(dayTimes, t.TotalTime, shown[i]) {
+			fmt.Fprintf(file, " %s |", formatDayTime(d))
+		}
+		fmt.Fprintf(file, "\n")
+		writeAnnotationRows(file, t, strings.Count(timeColumns, "|")+2+len(days))
 	}
 	fmt.Fprintf(file, "\n")
 }
 
-func formatDayHours(task model.TaskSummary, day time.Weekday) string {
-	hours := task.DayTotals[day]
-	if hours <= 0 {
+// writeAnnotationRows prints a task's annotations as sub-rows beneath it,
+// leaving every column except Task empty.
+func writeAnnotationRows(file *os.File, task model.TaskSummary, columns int) {
+	for _, note := range task.Annotations {
+		fmt.Fprintf(file, "| | ↳ %s |%s\n", truncate(note, 55), strings.Repeat(" |", columns-2))
+	}
+}
+
+// roundTasks rounds the times of a task table so they add up to the rounded
+// time of the whole table.
+func roundTasks(tasks []model.TaskSummary, opts Options) []time.Duration {
+	times := make([]time.Duration, len(tasks))
+	var total time.Duration
+	for i, t := range tasks {
+		times[i] = t.TotalTime
+		total += t.TotalTime
+	}
+	return opts.Rounding.roundRows(times, total)
+}
+
+// roundBilledTasks rounds the billed times of a task table the same way.
+func roundBilledTasks(tasks []model.TaskSummary, opts Options) []time.Duration {
+	times := make([]time.Duration, len(tasks))
+	var total time.Duration
+	for i, t := range tasks {
+		times[i] = t.BilledTime
+		total += t.BilledTime
+	}
+	return opts.Rounding.roundRows(times, total)
+}
+
+func formatDayTime(d time.Duration) string {
+	if d <= 0 {
 		return ""
 	}
-	return formatDuration(hours)
+	return formatDuration(d)
 }
 
 func projectName(task model.TaskSummary) string {
@@ -496,54 +527,46 @@ func sortTasksByProject(tasks []model.TaskSummary) []model.TaskSummary {
 	return sorted
 }
 
-func writeTagSummary(file *os.File, tags map[string]float64, total float64) {
-	var tagList []string
-	for tag := range tags {
-		tagList = append(tagList, tag)
-	}
-	sort.Slice(tagList, func(i, j int) bool {
-		return tags[tagList[i]] > tags[tagList[j]]
-	})
-
-	fmt.Fprintf(file, "| Category | Time | Share |\n")
-	fmt.Fprintf(file, "|:---------|-----:|------:|\n")
+func writeTagSummary(file *os.File, tags map[string]time.Duration, total time.Duration, opts Options) {
+	writeShareSummary(file, "Category", tags, total, opts)
+}
 
-	for _, tag := range tagList {
-		hours := tags[tag]
-		pct := 0.0
-		if total > 0 {
-			pct = (hours / total) * 100
+// writeDimensionSummaries prints a share table for each configured dimension
+// that occurs in byDimension.
+func writeDimensionSummaries(file *os.File, byDimension map[string]map[string]time.Duration, total time.Duration, opts Options) {
+	for _, dimension := range opts.Dimensions {
+		if values := byDimension[dimension]; len(values) > 0 {
+			writeShareSummary(file, categoryTitle(dimension), values, total, opts)
 		}
-		fmt.Fprintf(file, "| %s | %s | %.0f%% |\n", strings.ReplaceAll(tag, "|", "\\|"), formatDuration(hours), pct)
 	}
-	fmt.Fprintf(file, "\n")
 }
 
-func writeProjectSummary(file *os.File, projects map[string]float64, total float64) {
-	var projectList []string
-	for project := range projects {
-		projectList = append(projectList, project)
+func writeShareSummary(file *os.File, heading string, values map[string]time.Duration, total time.Duration, opts Options) {
+	fmt.Fprintf(file, "| %s | Time | Share |\n", heading)
+	fmt.Fprintf(file, "|:%s|-----:|------:|\n", strings.Repeat("-", utf8.RuneCountInString(heading)+1))
+
+	for _, row := range sortedShareRows(values, total, opts) {
+		fmt.Fprintf(file, "| %s | %s | %.0f%% |\n", strings.ReplaceAll(row.label, "|", "\\|"), formatDuration(row.shown), sharePercent(row.d, total))
 	}
-	sort.Slice(projectList, func(i, j int) bool {
-		return projects[projectList[i]] > projects[projectList[j]]
-	})
+	fmt.Fprintf(file, "\n")
+}
 
+// writeProjectSummary prints the project tree as a table. Sub-projects are
+// indented with non-breaking spaces, which Markdown keeps inside cells.
+func writeProjectSummary(file *os.File, projects map[string]time.Duration, total time.Duration, opts Options) {
 	fmt.Fprintf(file, "| Project | Time | Share |\n")
 	fmt.Fprintf(file, "|:--------|-----:|------:|\n")
 
-	for _, project := range projectList {
-		hours := projects[project]
-		pct := 0.0
-		if total > 0 {
-			pct = (hours / total) * 100
-		}
-		fmt.Fprintf(file, "| %s | %s | %.0f%% |\n", strings.ReplaceAll(project, "|", "\\|"), formatDuration(hours), pct)
+	for _, row := range projectRows(projects, total, opts, "\u00a0\u00a0") {
+		fmt.Fprintf(file, "| %s | %s | %.0f%% |\n", strings.ReplaceAll(row.label, "|", "\\|"), formatDuration(row.shown), sharePercent(row.d, total))
 	}
 	fmt.Fprintf(file, "\n")
 }
 
-func formatDuration(hours float64) string {
-	totalMinutes := int(hours * 60)
+// formatDuration formats d in hours and minutes. Callers round d under the
+// report's policy first; any leftover seconds round to the nearest minute.
+func formatDuration(d time.Duration) string {
+	totalMinutes := int(d.Round(time.Minute) / time.Minute)
 	h := totalMinutes / 60
 	m := totalMinutes % 60
 
@@ -574,12 +597,12 @@ func birthdayCycleStart(t time.Time, month time.Month, day int) time.Time {
 
 func birthdayDayNumber(t time.Time, month time.Month, day int) int {
 	start := birthdayCycleStart(t, month, day)
-	days := int(t.Sub(start).Hours() / 24)
+	days := civil.DateOf(t).DaysSince(civil.DateOf(start))
 	return days + 1
 }
 
 func birthdayWeekNumber(t time.Time, month time.Month, day int) int {
 	start := birthdayCycleStart(t, month, day)
-	days := int(t.Sub(start).Hours() / 24)
+	days := civil.DateOf(t).DaysSince(civil.DateOf(start))
 	return (days / 7) + 1
 }
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestBirthdayNumbersAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone     string
+		birthday string // MM-DD
+		at       string // local time in the week after a clock change
+		wantDay  int
+		wantWeek int
+	}{
+		{"America/New_York", "03-08", "2025-03-14 23:30", 7, 1},
+		{"America/New_York", "03-08", "2025-03-15 00:00", 8, 2},
+		{"America/New_York", "10-30", "2025-11-05 12:00", 7, 1},
+		{"America/New_York", "10-30", "2025-11-06 00:00", 8, 2},
+		{"Europe/Berlin", "03-25", "2025-03-31 00:00", 7, 1},
+		{"Europe/Berlin", "03-25", "2025-04-01 00:00", 8, 2},
+		{"Europe/Berlin", "10-22", "2025-10-28 23:59", 7, 1},
+		{"Australia/Sydney", "04-01", "2025-04-07 00:00", 7, 1},
+		{"Australia/Sydney", "10-01", "2025-10-08 00:00", 8, 2},
+		{"Australia/Sydney", "10-06", "2025-10-05 12:00", 365, 53}, // the day before the birthday
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.at, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			birthday, _ := time.Parse("01-02", tt.birthday)
+			at, err := time.ParseInLocation("2006-01-02 15:04", tt.at, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := birthdayDayNumber(at, birthday.Month(), birthday.Day()); got != tt.wantDay {
+				t.Errorf("birthdayDayNumber = %d, want %d", got, tt.wantDay)
+			}
+			if got := birthdayWeekNumber(at, birthday.Month(), birthday.Day()); got != tt.wantWeek {
+				t.Errorf("birthdayWeekNumber = %d, want %d", got, tt.wantWeek)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// Options holds the user settings that shape how a report is labelled and
+// laid out, independent of the output format.
+type Options struct {
+	BirthdayMonth time.Month
+	BirthdayDay   int
+	// ISOWeeks labels weeks with their ISO 8601 number (WeekData.WeekNum)
+	// instead of counting them from the last birthday.
+	ISOWeeks bool
+	// Categories lists the task tables of day and week reports, in order,
+	// including the fallback category if there is one.
+	Categories []string
+	// ProjectDepth limits how many levels of the project tree are shown;
+	// deeper projects are folded into their ancestor. Zero shows all.
+	ProjectDepth int
+	// Dimensions lists the key:value tag keys (client, env, ...) that get a
+	// share table of their own, in order.
+	Dimensions []string
+	// Rounding decides how durations are shown in whole minutes; the zero
+	// value behaves like RoundBalanced.
+	Rounding Rounding
+	// ShowBilled adds billed time next to tracked time in totals and task
+	// tables, for reports with a billing policy.
+	ShowBilled bool
+	// WeekStart is the first weekday of the weekday columns in CSV and TSV
+	// task rows.
+	WeekStart time.Weekday
+	// ClockHours writes durations in CSV and TSV output as h:mm instead of
+	// decimal hours.
+	ClockHours bool
+}
+
+// duration formats a single value under the rounding policy.
+func (o Options) duration(d time.Duration) string {
+	return formatDuration(o.Rounding.round(d))
+}
+
+// total formats a report total, followed by its billed time when shown.
+func (o Options) total(tracked, billed time.Duration) string {
+	if !o.ShowBilled {
+		return o.duration(tracked)
+	}
+	return fmt.Sprintf("%s (billed %s)", o.duration(tracked), o.duration(billed))
+}
+
+// weekNumber is the number shown for a week in titles and chart labels.
+func (o Options) weekNumber(week model.WeekData) int {
+	if o.ISOWeeks {
+		return week.WeekNum
+	}
+	return birthdayWeekNumber(week.Start, o.BirthdayMonth, o.BirthdayDay)
+}
+
+// weekdaysFrom lists the seven weekdays in display order for a week that
+// begins on first.
+func weekdaysFrom(first time.Weekday) []time.Weekday {
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = (first + time.Weekday(i)) % 7
+	}
+	return days
+}
@@ -0,0 +1,316 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/pdf"
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+const (
+	pdfMargin   = 40.0
+	pdfRowH     = 16.0
+	pdfBodySize = 9.0
+)
+
+// pdfColumn is a column of a PDF table.
+type pdfColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+// pdfSheet lays content out top to bottom, starting a new page whenever the
+// next block would run past the bottom margin.
+type pdfSheet struct {
+	doc     *pdf.Document
+	page    *pdf.Page
+	y       float64
+	columns []pdfColumn
+}
+
+func newPDFSheet(width, height float64) *pdfSheet {
+	s := &pdfSheet{doc: pdf.New(width, height)}
+	s.newPage()
+	return s
+}
+
+func (s *pdfSheet) newPage() {
+	s.page = s.doc.AddPage()
+	s.y = pdfMargin
+}
+
+// need moves to a new page unless height points still fit on this one, and
+// reports whether it did.
+func (s *pdfSheet) need(height float64) bool {
+	_, pageHeight := s.doc.Size()
+	if s.y+height <= pageHeight-pdfMargin {
+		return false
+	}
+	s.newPage()
+	return true
+}
+
+func (s *pdfSheet) text(font pdf.Font, size float64, line string) {
+	s.need(size + 4)
+	s.y += size + 4
+	s.page.Text(pdfMargin, s.y, font, size, line)
+}
+
+func (s *pdfSheet) gap(height float64) {
+	s.y += height
+}
+
+// table starts a table with the given columns and draws its header.
+func (s *pdfSheet) table(columns []pdfColumn) {
+	s.columns = columns
+	s.need(2 * pdfRowH)
+	s.header()
+}
+
+func (s *pdfSheet) header() {
+	width := 0.0
+	for _, c := range s.columns {
+		width += c.width
+	}
+	s.page.FillRect(pdfMargin, s.y, width, pdfRowH, 0.88)
+	s.cells(s.titles(), pdf.HelveticaBold)
+	s.page.Line(pdfMargin, s.y, pdfMargin+width, s.y, 0.8, 0)
+}
+
+func (s *pdfSheet) titles() []string {
+	titles := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		titles[i] = c.title
+	}
+	return titles
+}
+
+// row draws a table row, repeating the header on a new page first if the
+// row does not fit.
+func (s *pdfSheet) row(cells []string, font pdf.Font) {
+	if s.need(pdfRowH) {
+		s.header()
+	}
+	s.cells(cells, font)
+	width := 0.0
+	for _, c := range s.columns {
+		width += c.width
+	}
+	s.page.Line(pdfMargin, s.y, pdfMargin+width, s.y, 0.3, 0.7)
+}
+
+func (s *pdfSheet) cells(cells []string, font pdf.Font) {
+	x := pdfMargin
+	baseline := s.y + pdfRowH - 4.5
+	for i, c := range s.columns {
+		text := pdf.Fit(font, pdfBodySize, c.width-8, cells[i])
+		switch {
+		case text == "":
+		case c.right:
+			s.page.TextRight(x+c.width-4, baseline, font, pdfBodySize, text)
+		default:
+			s.page.Text(x+4, baseline, font, pdfBodySize, text)
+		}
+		x += c.width
+	}
+	s.y += pdfRowH
+}
+
+// signatures draws signature and date lines for each party, side by side.
+func (s *pdfSheet) signatures(parties ...string) {
+	s.need(80)
+	s.gap(50)
+	width, _ := s.doc.Size()
+	columnWidth := (width - 2*pdfMargin) / float64(len(parties))
+	for i, party := range parties {
+		x := pdfMargin + float64(i)*columnWidth
+		s.page.Line(x, s.y, x+columnWidth*0.55, s.y, 0.6, 0)
+		s.page.Line(x+columnWidth*0.62, s.y, x+columnWidth*0.9, s.y, 0.6, 0)
+		s.page.Text(x, s.y+12, pdf.Helvetica, pdfBodySize, party+" signature")
+		s.page.Text(x+columnWidth*0.62, s.y+12, pdf.Helvetica, pdfBodySize, "Date")
+	}
+	s.gap(16)
+}
+
+// TimesheetPDF writes a printable timesheet for the range: a header with the
+// client and period, then a day × task table per week like the week report's
+// category tables, the total and signature lines for both parties.
+func TimesheetPDF(file *os.File, report model.MonthData, start, end time.Time, opts Options) error {
+	s := newPDFSheet(pdf.A4Height, pdf.A4Width)
+
+	var tasks []model.TaskSummary
+	for _, week := range report.Weeks {
+		tasks = append(tasks, week.Tasks...)
+	}
+	s.text(pdf.HelveticaBold, 20, "Timesheet")
+	s.gap(6)
+	if clients := timesheetClients(tasks); clients != "" {
+		s.text(pdf.Helvetica, 11, "Client: "+clients)
+	}
+	s.text(pdf.Helvetica, 11, "Period: "+pdfPeriod(start, end))
+	s.gap(10)
+
+	if len(report.Weeks) == 0 {
+		s.text(pdf.Helvetica, 11, "No entries found.")
+	}
+	for _, week := range report.Weeks {
+		s.need(4 * pdfRowH)
+		s.text(pdf.HelveticaBold, 12, fmt.Sprintf("Week %d · %s – %s",
+			opts.weekNumber(week), week.Start.Format("Mon, Jan 2"), week.End.Format("Mon, Jan 2")))
+		s.gap(4)
+		writeTimesheetWeek(s, week, opts)
+		s.gap(12)
+	}
+
+	s.need(pdfRowH)
+	s.text(pdf.HelveticaBold, 12, "Total: "+opts.total(report.Total, report.Billed))
+	s.signatures("Consultant", "Client")
+
+	_, err := s.doc.WriteTo(file)
+	return err
+}
+
+// writeTimesheetWeek draws one week's tasks against its days, with a closing
+// row of daily totals.
+func writeTimesheetWeek(s *pdfSheet, week model.WeekData, opts Options) {
+	days := weekdaysFrom(week.Start.Weekday())
+	taskWidth := 280.0
+	if opts.ShowBilled {
+		taskWidth -= 60
+	}
+	columns := []pdfColumn{{title: "Project", width: 110}, {title: "Task", width: taskWidth}}
+	for _, day := range days {
+		columns = append(columns, pdfColumn{title: day.String()[:3], width: 44, right: true})
+	}
+	columns = append(columns, pdfColumn{title: "Total", width: 60, right: true})
+	if opts.ShowBilled {
+		columns = append(columns, pdfColumn{title: "Billed", width: 60, right: true})
+	}
+	s.table(columns)
+
+	sorted := sortTasksByProject(week.Tasks)
+	shown := roundTasks(sorted, opts)
+	billed := roundBilledTasks(sorted, opts)
+	for i, t := range sorted {
+		cells := []string{projectName(t), t.Description}
+		dayTimes := make([]time.Duration, len(days))
+		for j, day := range days {
+			dayTimes[j] = t.DayTotals[day]
+		}
+		for _, d := range opts.Rounding.roundRowsTo(dayTimes, t.TotalTime, shown[i]) {
+			cells = append(cells, formatDayTime(d))
+		}
+		cells = append(cells, formatDuration(shown[i]))
+		if opts.ShowBilled {
+			cells = append(cells, formatDuration(billed[i]))
+		}
+		s.row(cells, pdf.Helvetica)
+	}
+
+	dayTotals := make([]time.Duration, len(days))
+	for _, t := range week.Tasks {
+		for j, day := range days {
+			dayTotals[j] += t.DayTotals[day]
+		}
+	}
+	totals := []string{"Total", ""}
+	for _, d := range opts.Rounding.roundRows(dayTotals, week.Total) {
+		totals = append(totals, formatDayTime(d))
+	}
+	totals = append(totals, opts.duration(week.Total))
+	if opts.ShowBilled {
+		totals = append(totals, opts.duration(week.Billed))
+	}
+	s.row(totals, pdf.HelveticaBold)
+}
+
+// InvoicePDF writes a billing report as a printable invoice: the period, the
+// client → project → task table with rates and amounts, then the subtotal,
+// tax lines and total.
+func InvoicePDF(file *os.File, report model.BillingReport, opts Options) error {
+	s := newPDFSheet(pdf.A4Width, pdf.A4Height)
+
+	s.text(pdf.HelveticaBold, 20, "Invoice")
+	s.gap(6)
+	names := make([]string, len(report.Clients))
+	for i, c := range report.Clients {
+		names[i] = c.Name
+	}
+	if len(names) > 0 {
+		s.text(pdf.Helvetica, 11, "Client: "+strings.Join(names, ", "))
+	}
+	if !report.Start.IsZero() && !report.End.IsZero() {
+		s.text(pdf.Helvetica, 11, "Period: "+pdfPeriod(report.Start, report.End))
+	}
+	s.gap(10)
+
+	if len(report.Clients) == 0 {
+		s.text(pdf.Helvetica, 11, "No billable entries found.")
+	} else {
+		headers, rows, levels := billingTable(report, "    ", opts)
+		widths := []float64{195, 55, 50, 50, 75, 90}
+		columns := make([]pdfColumn, len(headers))
+		for i, h := range headers {
+			columns[i] = pdfColumn{title: h, width: widths[i], right: i > 0}
+		}
+		columns[0].title = "Description"
+		s.table(columns)
+		for i, row := range rows {
+			font := pdf.Helvetica
+			if levels[i] == billingClient {
+				font = pdf.HelveticaBold
+			}
+			s.row(row, font)
+		}
+	}
+
+	s.gap(12)
+	width, _ := s.doc.Size()
+	right := width - pdfMargin
+	for _, line := range billingSummary(report, opts) {
+		font := pdf.Helvetica
+		if line[0] == "Total" {
+			font = pdf.HelveticaBold
+		}
+		s.need(pdfRowH)
+		s.y += pdfRowH
+		s.page.TextRight(right-110, s.y, font, 10, line[0])
+		s.page.TextRight(right, s.y, font, 10, line[1])
+	}
+
+	_, err := s.doc.WriteTo(file)
+	return err
+}
+
+// pdfPeriod formats a range whose end is exclusive, naming its last day.
+func pdfPeriod(start, end time.Time) string {
+	return fmt.Sprintf("%s – %s",
+		start.Format("Mon, Jan 2, 2006"), end.Add(-time.Nanosecond).Format("Mon, Jan 2, 2006"))
+}
+
+// timesheetClients names the clients a timesheet covers: each task's client
+// dimension, else the top level of its project.
+func timesheetClients(tasks []model.TaskSummary) string {
+	seen := make(map[string]bool)
+	for _, t := range tasks {
+		client := t.Dimensions["client"]
+		if client == "" && t.Project != "unknown" {
+			client, _, _ = strings.Cut(t.Project, ".")
+			client, _, _ = strings.Cut(client, "/")
+		}
+		if client != "" {
+			seen[client] = true
+		}
+	}
+	clients := make([]string, 0, len(seen))
+	for client := range seen {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+	return strings.Join(clients, ", ")
+}
@@ -0,0 +1,240 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// PNG charts are drawn pixel by pixel with the embedded pngFont and the SVG
+// palette, without anti-aliasing, so the same data always encodes to the same
+// bytes. They follow the layout of the SVG charts.
+
+const (
+	pngScale   = 2              // image pixels per font pixel
+	pngAdvance = 6 * pngScale   // glyph width plus letter spacing
+	pngGlyphH  = 7 * pngScale   // glyph height
+	pngLine    = pngScale       // axis and rule thickness
+	pngPad     = 8 * pngScale   // margin around the chart
+	pngPlot    = 80 * pngScale  // height of a column chart's plot area
+	pngBar     = 120 * pngScale // length of a full share bar
+)
+
+// pngPalette holds every color a chart uses; the background comes first.
+var pngPalette = func() color.Palette {
+	p := color.Palette{color.White}
+	for _, hex := range append([]string{svgInk, svgSubtle, svgTrack, svgAccent, svgShare}, svgSeries...) {
+		p = append(p, pngColor(hex))
+	}
+	return p
+}()
+
+// pngColor parses a "#rrggbb" color.
+func pngColor(hex string) color.Color {
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// WriteChartPNG writes a chart as a PNG image.
+func WriteChartPNG(w io.Writer, c Chart) error {
+	var img *image.Paletted
+	switch c.kind {
+	case shareChart:
+		img = pngShareChart(c.rows, c.total)
+	case stackedChart:
+		img = pngStackedChart(c.stack)
+	default:
+		img = pngColumnChart(c.columns, c.peak)
+	}
+	return png.Encode(w, img)
+}
+
+// pngCanvas draws on a paletted image.
+type pngCanvas struct {
+	img *image.Paletted
+}
+
+func newPNGCanvas(width, height int) pngCanvas {
+	return pngCanvas{img: image.NewPaletted(image.Rect(0, 0, width, height), pngPalette)}
+}
+
+// fill paints a rectangle with its top-left corner at (x, y).
+func (c pngCanvas) fill(x, y, width, height int, hex string) {
+	index := uint8(pngPalette.Index(pngColor(hex)))
+	r := image.Rect(x, y, x+width, y+height).Intersect(c.img.Rect)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			c.img.SetColorIndex(px, py, index)
+		}
+	}
+}
+
+// dashed draws a horizontal dashed rule from x1 to x2.
+func (c pngCanvas) dashed(x1, x2, y int, hex string) {
+	for x := x1; x < x2; x += 4 * pngScale {
+		c.fill(x, y, min(2*pngScale, x2-x), pngLine, hex)
+	}
+}
+
+// text draws s with its top-left corner at (x, y). Characters outside
+// printable ASCII print as "?", except no-break spaces, which print as spaces.
+func (c pngCanvas) text(x, y int, s, hex string) {
+	for _, r := range s {
+		switch {
+		case r == '\u00a0':
+			r = ' '
+		case r < ' ' || r > '~':
+			r = '?'
+		}
+		for row, bits := range pngFont[r-' '] {
+			for col := range 5 {
+				if bits&(0x10>>col) != 0 {
+					c.fill(x+col*pngScale, y+row*pngScale, pngScale, pngScale, hex)
+				}
+			}
+		}
+		x += pngAdvance
+	}
+}
+
+// pngTextWidth is the width of s in pixels, without trailing letter spacing.
+func pngTextWidth(s string) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return n*pngAdvance - pngScale
+}
+
+// pngColumnChart draws columns like svgColumnChart: values above, labels
+// below and the peak marked by a dashed rule.
+func pngColumnChart(columns []chartColumn, peakLabel string) *image.Paletted {
+	const gap = 4 * pngScale
+	top := pngPad + pngGlyphH + gap
+	axis := pngPad + pngTextWidth(peakLabel) + gap
+	columnWidth := 20 * pngScale
+	for _, col := range columns {
+		columnWidth = max(columnWidth, pngTextWidth(col.top)+2*gap, pngTextWidth(col.bottom)+2*gap)
+	}
+	width := axis + len(columns)*columnWidth + pngPad
+	baseline := top + pngPlot
+	height := baseline + gap + pngGlyphH + pngPad
+
+	c := newPNGCanvas(width, height)
+	c.dashed(axis, width-pngPad, top, svgSubtle)
+	c.text(axis-gap-pngTextWidth(peakLabel), top-pngGlyphH/2, peakLabel, svgSubtle)
+	c.fill(axis, baseline, width-pngPad-axis, pngLine, svgSubtle)
+
+	for i, col := range columns {
+		x := axis + i*columnWidth
+		center := x + columnWidth/2
+		barHeight := int(math.Round(min(max(col.ratio, 0), 1) * pngPlot))
+		c.fill(x+gap/2, baseline-barHeight, columnWidth-gap, barHeight, svgAccent)
+		if col.bottom != "" {
+			c.text(center-pngTextWidth(col.bottom)/2, baseline-barHeight-gap-pngGlyphH, col.bottom, svgInk)
+		}
+		c.text(center-pngTextWidth(col.top)/2, baseline+pngLine+gap, col.top, svgSubtle)
+	}
+	return c.img
+}
+
+// pngShareChart draws labelled bars like svgShareChart, each followed by its
+// time and share of total.
+func pngShareChart(rows []chartRow, total time.Duration) *image.Paletted {
+	const (
+		gap       = 6 * pngScale
+		rowHeight = pngGlyphH + gap
+	)
+	var largest time.Duration
+	labelWidth, timeWidth := 0, 0
+	for _, r := range rows {
+		largest = maxDuration(largest, r.d)
+		labelWidth = max(labelWidth, pngTextWidth(r.label))
+		timeWidth = max(timeWidth, pngTextWidth(formatDuration(r.shown)))
+	}
+	barX := pngPad + labelWidth + gap
+	timeRight := barX + pngBar + gap + timeWidth
+	shareRight := timeRight + gap + pngTextWidth("100%")
+	width := shareRight + pngPad
+	height := 2*pngPad + len(rows)*rowHeight - gap
+
+	c := newPNGCanvas(width, height)
+	for i, r := range rows {
+		y := pngPad + i*rowHeight
+		c.text(pngPad, y, r.label, svgInk)
+		c.fill(barX, y, pngBar, pngGlyphH, svgTrack)
+		if largest > 0 {
+			c.fill(barX, y, int(math.Round(float64(r.d)/float64(largest)*pngBar)), pngGlyphH, svgAccent)
+		}
+		shown := formatDuration(r.shown)
+		c.text(timeRight-pngTextWidth(shown), y, shown, svgInk)
+		share := strconv.Itoa(int(math.Round(sharePercent(r.d, total)))) + "%"
+		c.text(shareRight-pngTextWidth(share), y, share, svgShare)
+	}
+	return c.img
+}
+
+// pngStackedChart draws stacked columns like svgStackedChart, with a legend
+// of the series underneath.
+func pngStackedChart(stack stackedColumns) *image.Paletted {
+	const (
+		gap    = 4 * pngScale
+		swatch = pngGlyphH
+	)
+	top := pngPad + pngGlyphH + gap
+	columnWidth := 20 * pngScale
+	for i, label := range stack.labels {
+		columnWidth = max(columnWidth, pngTextWidth(label)+2*gap, pngTextWidth(stack.totals[i])+2*gap)
+	}
+	width := max(2*pngPad+len(stack.labels)*columnWidth, 160*pngScale)
+	baseline := top + pngPlot
+
+	// Lay the legend out in rows that fit the chart's width.
+	type legendItem struct{ x, y int }
+	legend := make([]legendItem, len(stack.series))
+	x, y := pngPad, baseline+pngLine+2*gap+pngGlyphH+2*gap
+	for i, name := range stack.series {
+		itemWidth := swatch + gap + pngTextWidth(name) + 3*gap
+		if x > pngPad && x+itemWidth > width-pngPad {
+			x, y = pngPad, y+pngGlyphH+gap
+		}
+		legend[i] = legendItem{x, y}
+		x += itemWidth
+		width = max(width, 2*pngPad+itemWidth)
+	}
+	height := y + pngGlyphH + pngPad
+
+	c := newPNGCanvas(width, height)
+	c.fill(pngPad, baseline, width-2*pngPad, pngLine, svgSubtle)
+	for i, values := range stack.values {
+		x := pngPad + i*columnWidth
+		center := x + columnWidth/2
+		// Round the running top rather than each segment, so the column's
+		// height does not drift from its total.
+		var sum time.Duration
+		y := baseline
+		for j, d := range values {
+			if d <= 0 || stack.largest <= 0 {
+				continue
+			}
+			sum += d
+			next := baseline - int(math.Round(float64(sum)/float64(stack.largest)*pngPlot))
+			c.fill(x+gap/2, next, columnWidth-gap, y-next, svgSeries[j%len(svgSeries)])
+			y = next
+		}
+		if stack.totals[i] != "" {
+			c.text(center-pngTextWidth(stack.totals[i])/2, y-gap-pngGlyphH, stack.totals[i], svgInk)
+		}
+		c.text(center-pngTextWidth(stack.labels[i])/2, baseline+pngLine+gap, stack.labels[i], svgSubtle)
+	}
+	for i, name := range stack.series {
+		l := legend[i]
+		c.fill(l.x, l.y, swatch, swatch, svgSeries[i%len(svgSeries)])
+		c.text(l.x+swatch+gap, l.y, name, svgInk)
+	}
+	return c.img
+}
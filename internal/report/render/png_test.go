@@ -0,0 +1,46 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// TestWriteChartPNGIsReproducible builds and draws every week and month chart
+// twice: the same report must give byte-identical files, whatever order maps
+// are walked in.
+func TestWriteChartPNGIsReproducible(t *testing.T) {
+	week, _, _, opts := sampleWeek()
+	month := model.MonthData{Month: time.September, Weeks: []model.WeekData{week, week}, Total: 2 * week.Total}
+	draw := func() map[string][]byte {
+		files := make(map[string][]byte)
+		for _, c := range append(WeekCharts(week, opts), MonthCharts(month, opts)...) {
+			var buf bytes.Buffer
+			if err := WriteChartPNG(&buf, c); err != nil {
+				t.Fatal(err)
+			}
+			files[c.Name] = buf.Bytes()
+		}
+		return files
+	}
+
+	first, second := draw(), draw()
+	if len(first) == 0 {
+		t.Fatal("no charts drawn")
+	}
+	for name, data := range first {
+		if !bytes.Equal(data, second[name]) {
+			t.Errorf("%s: two renders of the same report differ", name)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if b := img.Bounds(); b.Dx() == 0 || b.Dy() == 0 {
+			t.Errorf("%s: image is %dx%d", name, b.Dx(), b.Dy())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"sort"
+	"time"
+)
+
+// Rounding is the policy for showing tracked time in whole minutes.
+type Rounding string
+
+const (
+	// RoundBalanced rounds to the nearest minute, then moves single minutes
+	// between the rows of a table so they add up to its rounded total
+	// (largest remainder method).
+	RoundBalanced Rounding = "balanced"
+	// RoundNearest rounds every value to the nearest minute on its own.
+	RoundNearest Rounding = "nearest"
+	// RoundDown drops partial minutes.
+	RoundDown Rounding = "down"
+	// RoundUp counts a partial minute as a whole one.
+	RoundUp Rounding = "up"
+)
+
+// round rounds a single value to whole minutes.
+func (r Rounding) round(d time.Duration) time.Duration {
+	return r.roundIn(time.Minute, d)
+}
+
+// roundIn rounds a single value to a whole number of unit.
+func (r Rounding) roundIn(unit, d time.Duration) time.Duration {
+	switch r {
+	case RoundDown:
+		return d.Truncate(unit)
+	case RoundUp:
+		if t := d.Truncate(unit); t != d {
+			return t + unit
+		}
+		return d
+	default:
+		return d.Round(unit)
+	}
+}
+
+// roundRows rounds the rows of a table whose total is shown as r.round(total).
+func (r Rounding) roundRows(rows []time.Duration, total time.Duration) []time.Duration {
+	return r.roundRowsTo(rows, total, r.round(total))
+}
+
+// roundRowsTo rounds rows to whole minutes. Under balanced rounding, rows that
+// add up exactly to total are adjusted to add up to shownTotal, the total as
+// displayed; rows that overlap or leave time out (a task counted under two
+// tags, a project with time of its own) cannot be balanced and are rounded
+// one by one.
+func (r Rounding) roundRowsTo(rows []time.Duration, total, shownTotal time.Duration) []time.Duration {
+	return r.roundRowsIn(time.Minute, rows, total, shownTotal)
+}
+
+// roundRowsIn is roundRowsTo for a unit other than the minute, such as the
+// hundredth of an hour of decimal exports.
+func (r Rounding) roundRowsIn(unit time.Duration, rows []time.Duration, total, shownTotal time.Duration) []time.Duration {
+	shown := make([]time.Duration, len(rows))
+	var sum time.Duration
+	for i, d := range rows {
+		shown[i] = r.roundIn(unit, d)
+		sum += d
+	}
+	if balanced := r == RoundBalanced || r == ""; !balanced || sum != total {
+		return shown
+	}
+
+	var floorSum time.Duration
+	for i, d := range rows {
+		shown[i] = d.Truncate(unit)
+		floorSum += shown[i]
+	}
+	extra := int((shownTotal - floorSum) / unit)
+	if extra <= 0 || extra > len(rows) {
+		return shown
+	}
+
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rows[order[a]]-shown[order[a]] > rows[order[b]]-shown[order[b]]
+	})
+	for _, i := range order[:extra] {
+		shown[i] += unit
+	}
+	return shown
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRoundRows(t *testing.T) {
+	s := time.Second
+	tests := []struct {
+		name  string
+		mode  Rounding
+		rows  []time.Duration
+		total time.Duration
+		want  []time.Duration
+	}{
+		{"balanced thirds", RoundBalanced, []time.Duration{20 * s, 20 * s, 20 * s}, 60 * s, []time.Duration{time.Minute, 0, 0}},
+		{"balanced largest remainder", RoundBalanced, []time.Duration{70 * s, 100 * s, 90 * s}, 260 * s, []time.Duration{time.Minute, 2 * time.Minute, time.Minute}},
+		{"default is balanced", "", []time.Duration{20 * s, 20 * s, 20 * s}, 60 * s, []time.Duration{time.Minute, 0, 0}},
+		{"nearest thirds", RoundNearest, []time.Duration{20 * s, 20 * s, 20 * s}, 60 * s, []time.Duration{0, 0, 0}},
+		{"down", RoundDown, []time.Duration{119 * s, 61 * s}, 180 * s, []time.Duration{time.Minute, time.Minute}},
+		{"up", RoundUp, []time.Duration{61 * s, 60 * s}, 121 * s, []time.Duration{2 * time.Minute, time.Minute}},
+		{"balanced overlap", RoundBalanced, []time.Duration{40 * s, 40 * s}, 40 * s, []time.Duration{time.Minute, time.Minute}},
+		{"balanced gap", RoundBalanced, []time.Duration{20 * s, 20 * s}, 60 * s, []time.Duration{0, 0}},
+		{"no rows", RoundBalanced, nil, 0, []time.Duration{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.roundRows(tt.rows, tt.total); !slices.Equal(got, tt.want) {
+				t.Errorf("roundRows(%v, %v) = %v, want %v", tt.rows, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoundRowsProperties checks, on random tables, what each mode promises:
+// balanced rows that add up to the total are shown adding up to the shown
+// total, and every other row is its own value rounded by the mode.
+func TestRoundRowsProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	modes := []Rounding{RoundBalanced, RoundNearest, RoundDown, RoundUp}
+
+	for i := 0; i < 2000; i++ {
+		rows := make([]time.Duration, 1+rng.Intn(8))
+		var total time.Duration
+		for j := range rows {
+			rows[j] = time.Duration(rng.Intn(3*3600)) * time.Second
+			total += rows[j]
+		}
+		overlapping := total + time.Duration(1+rng.Intn(600))*time.Second
+		if rng.Intn(2) == 0 {
+			overlapping = total - time.Duration(1+rng.Intn(int(total/time.Second)+1))*time.Second
+		}
+
+		for _, mode := range modes {
+			shown := mode.roundRows(rows, total)
+			checkWithinMinute(t, mode, rows, shown)
+			sum := sumDurations(shown)
+			switch mode {
+			case RoundBalanced:
+				if want := mode.round(total); sum != want {
+					t.Fatalf("%s %v: rows sum to %v, want %v", mode, rows, sum, want)
+				}
+			case RoundDown:
+				if sum > mode.round(total) {
+					t.Fatalf("%s %v: rows sum to %v, more than the total %v", mode, rows, sum, mode.round(total))
+				}
+			case RoundUp:
+				if sum < mode.round(total) {
+					t.Fatalf("%s %v: rows sum to %v, less than the total %v", mode, rows, sum, mode.round(total))
+				}
+			}
+			if mode != RoundBalanced {
+				checkRoundedOneByOne(t, mode, rows, shown)
+			}
+
+			// Rows that do not add up to their total are never balanced.
+			checkRoundedOneByOne(t, mode, rows, mode.roundRows(rows, overlapping))
+		}
+
+		// A nested table is balanced to its parent row as shown, which can
+		// be either whole minute next to the parent's time.
+		for _, shownTotal := range []time.Duration{total.Truncate(time.Minute), RoundUp.round(total)} {
+			shown := RoundBalanced.roundRowsTo(rows, total, shownTotal)
+			checkWithinMinute(t, RoundBalanced, rows, shown)
+			if sum := sumDurations(shown); sum != shownTotal {
+				t.Fatalf("roundRowsTo(%v, %v, %v): rows sum to %v", rows, total, shownTotal, sum)
+			}
+		}
+	}
+}
+
+func checkWithinMinute(t *testing.T, mode Rounding, rows, shown []time.Duration) {
+	t.Helper()
+	for j, d := range shown {
+		if d%time.Minute != 0 || d-rows[j] >= time.Minute || rows[j]-d >= time.Minute {
+			t.Fatalf("%s %v: row %d shown as %v", mode, rows, j, d)
+		}
+	}
+}
+
+func checkRoundedOneByOne(t *testing.T, mode Rounding, rows, shown []time.Duration) {
+	t.Helper()
+	for j, d := range shown {
+		if want := mode.round(rows[j]); d != want {
+			t.Fatalf("%s %v: row %d shown as %v, want %v", mode, rows, j, d, want)
+		}
+	}
+}
+
+func sumDurations(ds []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	return sum
+}
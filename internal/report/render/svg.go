@@ -0,0 +1,220 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/pdf"
+)
+
+// SVG charts mirror the terminal charts with the same palette. They are
+// complete <svg> documents, so they can be inlined in HTML or saved as files.
+// Text is measured with Helvetica metrics; other sans-serif fonts are close
+// enough for laying out labels.
+
+const (
+	svgFontFamily = "Helvetica, Arial, sans-serif"
+	svgFontSize   = 12.0
+
+	svgAccent = "#5f87af" // colorAccent
+	svgShare  = "#5faf87" // colorShare
+	svgTrack  = "#e4e4e4"
+	svgInk    = "#303030"
+	svgSubtle = "#8a8a8a" // colorSubtle
+)
+
+// svgSeries colors the segments of stacked columns, in series order.
+var svgSeries = []string{"#5f87af", "#5faf87", "#d7af5f", "#af5f87", "#87afd7", "#af875f", "#5fafaf", "#8787af"}
+
+// WriteChartSVG writes a chart as a standalone SVG file.
+func WriteChartSVG(w io.Writer, c Chart) error {
+	_, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+chartSVG(c))
+	return err
+}
+
+// chartSVG draws a chart as an <svg> element.
+func chartSVG(c Chart) string {
+	switch c.kind {
+	case shareChart:
+		return svgShareChart(c.Title, c.rows, c.total)
+	case stackedChart:
+		return svgStackedChart(c.Title, c.stack)
+	default:
+		return svgColumnChart(c.Title, c.columns, c.peak)
+	}
+}
+
+// svgTextWidth estimates the rendered width of s at the chart font size.
+func svgTextWidth(s string) float64 {
+	return pdf.TextWidth(pdf.Helvetica, svgFontSize, s)
+}
+
+func svgOpen(b *strings.Builder, title string, width, height float64) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" `+
+		`role="img" aria-label="%s" font-family="%s" font-size="%s">`+"\n",
+		svgNum(width), svgNum(height), svgNum(width), svgNum(height),
+		html.EscapeString(title), svgFontFamily, svgNum(svgFontSize))
+	fmt.Fprintf(b, "<title>%s</title>\n", html.EscapeString(title))
+}
+
+func svgText(b *strings.Builder, x, y float64, anchor, fill, weight, s string) {
+	fmt.Fprintf(b, `<text x="%s" y="%s" text-anchor="%s" fill="%s" font-weight="%s">%s</text>`+"\n",
+		svgNum(x), svgNum(y), anchor, fill, weight, html.EscapeString(s))
+}
+
+// svgColumnChart draws columns rising from a baseline, each labelled below
+// (c.top, e.g. "Mon") and valued above (c.bottom), with the peak marked by a
+// dashed line. It mirrors writeColorVerticalChart.
+func svgColumnChart(title string, columns []chartColumn, peakLabel string) string {
+	const (
+		plotHeight = 140.0
+		top        = 24.0
+		gap        = 8.0
+	)
+	axisWidth := svgTextWidth(peakLabel) + 12
+	columnWidth := 36.0
+	for _, c := range columns {
+		columnWidth = max(columnWidth, svgTextWidth(c.top)+gap, svgTextWidth(c.bottom)+gap)
+	}
+	width := axisWidth + float64(len(columns))*columnWidth + gap
+	height := top + plotHeight + 24
+	baseline := top + plotHeight
+
+	var b strings.Builder
+	svgOpen(&b, title, width, height)
+	fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-dasharray="3 3"/>`+"\n",
+		svgNum(axisWidth), svgNum(top), svgNum(width), svgNum(top), svgSubtle)
+	svgText(&b, axisWidth-6, top+4, "end", svgSubtle, "normal", peakLabel)
+	fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n",
+		svgNum(axisWidth), svgNum(baseline), svgNum(width), svgNum(baseline), svgSubtle)
+
+	for i, c := range columns {
+		x := axisWidth + float64(i)*columnWidth
+		center := x + columnWidth/2
+		barHeight := min(max(c.ratio, 0), 1) * plotHeight
+		if barHeight > 0 {
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" rx="2" fill="%s"/>`+"\n",
+				svgNum(x+gap/2), svgNum(baseline-barHeight), svgNum(columnWidth-gap), svgNum(barHeight), svgAccent)
+		}
+		if c.bottom != "" {
+			svgText(&b, center, baseline-barHeight-5, "middle", svgInk, "normal", c.bottom)
+		}
+		svgText(&b, center, baseline+17, "middle", svgSubtle, "normal", c.top)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgShareChart draws labelled horizontal bars scaled to the largest row,
+// each followed by its time and share of total. It mirrors writeShareRows.
+func svgShareChart(title string, rows []chartRow, total time.Duration) string {
+	const (
+		rowHeight = 22.0
+		barWidth  = 240.0
+	)
+	var largest time.Duration
+	labelWidth := 0.0
+	for _, r := range rows {
+		largest = maxDuration(largest, r.d)
+		labelWidth = max(labelWidth, svgTextWidth(r.label))
+	}
+	labelWidth += 12
+	timeWidth := 70.0
+	shareWidth := 48.0
+	width := labelWidth + barWidth + timeWidth + shareWidth
+	height := float64(len(rows)) * rowHeight
+
+	var b strings.Builder
+	svgOpen(&b, title, width, height)
+	for i, r := range rows {
+		y := float64(i) * rowHeight
+		ratio := 0.0
+		if largest > 0 {
+			ratio = float64(r.d) / float64(largest)
+		}
+		svgText(&b, 0, y+15, "start", svgInk, "normal", r.label)
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="12" rx="2" fill="%s"/>`+"\n",
+			svgNum(labelWidth), svgNum(y+5), svgNum(barWidth), svgTrack)
+		if ratio > 0 {
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="12" rx="2" fill="%s"/>`+"\n",
+				svgNum(labelWidth), svgNum(y+5), svgNum(ratio*barWidth), svgAccent)
+		}
+		svgText(&b, labelWidth+barWidth+timeWidth-6, y+15, "end", svgInk, "normal", formatDuration(r.shown))
+		svgText(&b, width, y+15, "end", svgShare, "normal", fmt.Sprintf("%.0f%%", sharePercent(r.d, total)))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgStackedChart draws columns split into one colored segment per series,
+// stacked in series order from the baseline, each labelled below and totalled
+// above, with a legend of the series underneath.
+func svgStackedChart(title string, stack stackedColumns) string {
+	const (
+		plotHeight = 140.0
+		top        = 24.0
+		gap        = 8.0
+		legendRow  = 20.0
+	)
+	columnWidth := 36.0
+	for i, label := range stack.labels {
+		columnWidth = max(columnWidth, svgTextWidth(label)+gap, svgTextWidth(stack.totals[i])+gap)
+	}
+	width := max(float64(len(stack.labels))*columnWidth+gap, 240)
+	baseline := top + plotHeight
+
+	// Lay the legend out in rows that fit the chart's width.
+	type legendItem struct{ x, y float64 }
+	legend := make([]legendItem, len(stack.series))
+	x, y := 0.0, baseline+44
+	for i, name := range stack.series {
+		itemWidth := 18 + svgTextWidth(name) + 16
+		if x > 0 && x+itemWidth > width {
+			x, y = 0, y+legendRow
+		}
+		legend[i] = legendItem{x, y}
+		x += itemWidth
+		width = max(width, itemWidth)
+	}
+	height := y + 8
+
+	var b strings.Builder
+	svgOpen(&b, title, width, height)
+	fmt.Fprintf(&b, `<line x1="0" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n",
+		svgNum(baseline), svgNum(width), svgNum(baseline), svgSubtle)
+	for i, values := range stack.values {
+		x := float64(i) * columnWidth
+		y := baseline
+		for j, d := range values {
+			if d <= 0 || stack.largest <= 0 {
+				continue
+			}
+			h := float64(d) / float64(stack.largest) * plotHeight
+			y -= h
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s</title></rect>`+"\n",
+				svgNum(x+gap/2), svgNum(y), svgNum(columnWidth-gap), svgNum(h),
+				svgSeries[j%len(svgSeries)], html.EscapeString(stack.series[j]+": "+formatDuration(d)))
+		}
+		if stack.totals[i] != "" {
+			svgText(&b, x+columnWidth/2, y-5, "middle", svgInk, "normal", stack.totals[i])
+		}
+		svgText(&b, x+columnWidth/2, baseline+17, "middle", svgSubtle, "normal", stack.labels[i])
+	}
+	for i, name := range stack.series {
+		l := legend[i]
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="12" height="12" rx="2" fill="%s"/>`+"\n",
+			svgNum(l.x), svgNum(l.y-10), svgSeries[i%len(svgSeries)])
+		svgText(&b, l.x+18, l.y, "start", svgInk, "normal", name)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgNum formats a coordinate compactly, with at most one decimal.
+func svgNum(f float64) string {
+	s := fmt.Sprintf("%.1f", f)
+	return strings.TrimSuffix(s, ".0")
+}
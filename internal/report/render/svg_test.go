@@ -0,0 +1,117 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/report/model"
+)
+
+// TestWriteChartSVG checks that every week and month chart is a well-formed,
+// reproducible SVG document that keeps labels as text.
+func TestWriteChartSVG(t *testing.T) {
+	week, _, _, opts := sampleWeek()
+	week.ByTag["<b>R&D</b>"] = 20 * time.Minute
+	month := model.MonthData{Month: time.September, Weeks: []model.WeekData{week, week}, Total: 2 * week.Total}
+	draw := func() map[string]string {
+		files := make(map[string]string)
+		for _, c := range append(WeekCharts(week, opts), MonthCharts(month, opts)...) {
+			var buf bytes.Buffer
+			if err := WriteChartSVG(&buf, c); err != nil {
+				t.Fatal(err)
+			}
+			files[c.Name] = buf.String()
+		}
+		return files
+	}
+
+	first, second := draw(), draw()
+	if len(first) == 0 {
+		t.Fatal("no charts drawn")
+	}
+	escaped := false
+	for name, svg := range first {
+		if svg != second[name] {
+			t.Errorf("%s: two renders of the same report differ", name)
+		}
+		if !strings.HasPrefix(svg, `<?xml version="1.0" encoding="UTF-8"?>`+"\n<svg ") {
+			t.Errorf("%s: does not start with an XML declaration and <svg>", name)
+		}
+		if strings.Contains(svg, "<b>") {
+			t.Errorf("%s: a label was written as markup", name)
+		}
+		escaped = escaped || strings.Contains(svg, "&lt;b&gt;R&amp;D&lt;/b&gt;")
+
+		var texts []string
+		var root *xml.StartElement
+		d := xml.NewDecoder(strings.NewReader(svg))
+		for inText := false; ; {
+			tok, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				if root == nil {
+					root = &tok
+				}
+				inText = tok.Name.Local == "text"
+			case xml.CharData:
+				if inText {
+					texts = append(texts, string(tok))
+				}
+			case xml.EndElement:
+				inText = false
+			}
+		}
+		if root == nil || root.Name.Local != "svg" || root.Name.Space != "http://www.w3.org/2000/svg" {
+			t.Fatalf("%s: root element = %+v, want svg", name, root)
+		}
+		for _, attr := range []string{"width", "height", "viewBox"} {
+			if !hasAttr(root, attr) {
+				t.Errorf("%s: <svg> has no %s", name, attr)
+			}
+		}
+		if len(texts) == 0 {
+			t.Errorf("%s: no text labels", name)
+		}
+	}
+	if !escaped {
+		t.Error("the <b>R&D</b> tag is not shown escaped in any chart")
+	}
+}
+
+func hasAttr(e *xml.StartElement, name string) bool {
+	for _, a := range e.Attr {
+		if a.Name.Local == name && a.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSVGNum(t *testing.T) {
+	tests := []struct {
+		f    float64
+		want string
+	}{
+		{0, "0"},
+		{12, "12"},
+		{12.25, "12.2"},
+		{12.26, "12.3"},
+		{-3.04, "-3"},
+		{140.5, "140.5"},
+	}
+	for _, tt := range tests {
+		if got := svgNum(tt.f); got != tt.want {
+			t.Errorf("svgNum(%v) = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
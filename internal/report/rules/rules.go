@@ -0,0 +1,251 @@
+// Package rules categorizes entries from config instead of tags: each rule
+// matches on description, tags, project, weekday or time of day and assigns
+// a category, a project or extra tags.
+//
+// Rules are configured under reports.lume.rule.<name>, with match.* keys for
+// conditions and set.* keys for actions, and run in the order given by
+// reports.lume.rules (or by name when that list is absent):
+//
+//	reports.lume.rules = standup, review
+//	reports.lume.rule.standup.match.description = (?i)stand-?up
+//	reports.lume.rule.standup.set.category = meetings
+//	reports.lume.rule.review.match.tags = pr
+//	reports.lume.rule.review.match.time = 09:00-12:00
+//	reports.lume.rule.review.set.project = acme
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+const keyPrefix = "reports.lume.rule."
+
+// ruleKeys are the keys a rule is configured with, after its name.
+var ruleKeys = []string{
+	"match.description", "match.tags", "match.project", "match.weekdays", "match.time",
+	"set.category", "set.project", "set.tags",
+}
+
+// rule is a single configured rule. Empty conditions match everything; a rule
+// with no conditions at all applies to every entry.
+type rule struct {
+	name string
+
+	description *regexp.Regexp
+	tags        []string
+	project     string
+	weekdays    []time.Weekday
+	timeFrom    time.Duration // start time of day, inclusive
+	timeTo      time.Duration // end time of day, exclusive; before timeFrom wraps past midnight
+	hasTime     bool
+
+	category   string
+	setProject string
+	addTags    []string
+}
+
+// Set is an ordered list of rules plus the category names that count as an
+// entry already being categorized.
+type Set struct {
+	rules      []rule
+	categories []string
+}
+
+// FromConfig reads the rules from cfg. It returns nil when none are
+// configured; a nil Set applies no rules.
+func FromConfig(cfg timewarrior.TimewConfig) (*Set, error) {
+	names := ruleNames(cfg)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	set := &Set{}
+	set.categories, _ = cfg.Categories()
+	for _, name := range names {
+		rule, err := parseRule(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		set.rules = append(set.rules, rule)
+	}
+	return set, nil
+}
+
+// ruleNames returns the rule order from reports.lume.rules, or every name
+// found under reports.lume.rule.* sorted alphabetically. Names may contain
+// dots: the name is what precedes a known rule key such as .match.tags.
+func ruleNames(cfg timewarrior.TimewConfig) []string {
+	var names []string
+	for _, name := range strings.Split(cfg.Get("reports.lume.rules"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		return names
+	}
+
+	for key := range cfg.Values {
+		rest, ok := strings.CutPrefix(key, keyPrefix)
+		if !ok {
+			continue
+		}
+		name := ruleName(rest)
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ruleName returns the rule name in rest, a config key without keyPrefix.
+// A key that ends in no known rule key is taken to be named up to its first
+// dot, so parseRule reports the rule as incomplete.
+func ruleName(rest string) string {
+	for _, key := range ruleKeys {
+		if name, ok := strings.CutSuffix(rest, "."+key); ok {
+			return name
+		}
+	}
+	name, _, _ := strings.Cut(rest, ".")
+	return name
+}
+
+func parseRule(cfg timewarrior.TimewConfig, name string) (rule, error) {
+	r := rule{name: name}
+	get := func(key string) string {
+		return strings.TrimSpace(cfg.Get(keyPrefix + name + "." + key))
+	}
+	invalid := func(key, value, hint string) error {
+		return fmt.Errorf("invalid %s%s.%s %q (%s)", keyPrefix, name, key, value, hint)
+	}
+
+	if v := get("match.description"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return rule{}, invalid("match.description", v, err.Error())
+		}
+		r.description = re
+	}
+	r.tags = splitList(get("match.tags"))
+	r.project = get("match.project")
+	for _, day := range splitList(get("match.weekdays")) {
+		weekday, ok := timewarrior.ParseWeekday(day)
+		if !ok {
+			return rule{}, invalid("match.weekdays", day, "use weekday names such as mon,tue")
+		}
+		r.weekdays = append(r.weekdays, weekday)
+	}
+	if v := get("match.time"); v != "" {
+		from, to, ok := parseTimeRange(v)
+		if !ok {
+			return rule{}, invalid("match.time", v, "use HH:MM-HH:MM")
+		}
+		r.timeFrom, r.timeTo, r.hasTime = from, to, true
+	}
+
+	r.category = strings.ToLower(get("set.category"))
+	r.setProject = get("set.project")
+	r.addTags = splitList(get("set.tags"))
+	if r.category == "" && r.setProject == "" && len(r.addTags) == 0 {
+		return rule{}, fmt.Errorf("rule %q has no set.category, set.project or set.tags", name)
+	}
+	return r, nil
+}
+
+// Apply runs every rule against e in order and returns the updated entry.
+// Matching rules accumulate, but a category is only assigned to an entry
+// that has none yet and a project only to one without a project: tags the
+// user wrote always win over rules.
+func (s *Set) Apply(e timewarrior.Entry) timewarrior.Entry {
+	if s == nil {
+		return e
+	}
+	e.Tags = slices.Clone(e.Tags)
+	for _, rule := range s.rules {
+		if !rule.matches(e) {
+			continue
+		}
+		if rule.category != "" && !s.hasCategory(e) {
+			e.Tags = append(e.Tags, rule.category)
+		}
+		if rule.setProject != "" && e.Project() == "" {
+			e.Tags = append(e.Tags, "project:"+rule.setProject)
+		}
+		for _, tag := range rule.addTags {
+			if !slices.Contains(e.Tags, tag) {
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+	}
+	return e
+}
+
+func (s *Set) hasCategory(e timewarrior.Entry) bool {
+	for _, tag := range e.Tags {
+		if slices.Contains(s.categories, strings.ToLower(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) matches(e timewarrior.Entry) bool {
+	if r.description != nil && !r.description.MatchString(e.Description) {
+		return false
+	}
+	if !e.HasTags(r.tags) {
+		return false
+	}
+	if r.project != "" && !e.InProject(r.project) {
+		return false
+	}
+	if len(r.weekdays) > 0 && !slices.Contains(r.weekdays, e.Start.Weekday()) {
+		return false
+	}
+	if r.hasTime {
+		at := time.Duration(e.Start.Hour())*time.Hour + time.Duration(e.Start.Minute())*time.Minute
+		if r.timeFrom <= r.timeTo {
+			return at >= r.timeFrom && at < r.timeTo
+		}
+		return at >= r.timeFrom || at < r.timeTo
+	}
+	return true
+}
+
+func parseTimeRange(v string) (time.Duration, time.Duration, bool) {
+	fromText, toText, ok := strings.Cut(v, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	from, err := time.Parse("15:04", strings.TrimSpace(fromText))
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err := time.Parse("15:04", strings.TrimSpace(toText))
+	if err != nil {
+		return 0, 0, false
+	}
+	sinceMidnight := func(t time.Time) time.Duration {
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return sinceMidnight(from), sinceMidnight(to), true
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,242 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func config(values map[string]string) timewarrior.TimewConfig {
+	cfg := timewarrior.TimewConfig{Values: make(map[string]string)}
+	for key, value := range values {
+		if !strings.HasPrefix(key, "reports.") {
+			key = keyPrefix + key
+		}
+		cfg.Values[key] = value
+	}
+	return cfg
+}
+
+func TestFromConfig(t *testing.T) {
+	set, err := FromConfig(config(nil))
+	if err != nil || set != nil {
+		t.Fatalf("FromConfig without rules = %v, %v, want nil, nil", set, err)
+	}
+
+	set, err = FromConfig(config(map[string]string{
+		"zeta.set.tags":              "z",
+		"client.acme.match.tags":     "acme",
+		"client.acme.set.project":    "acme",
+		"alpha.match.time":           "09:00-12:00",
+		"alpha.set.category":         "Meetings",
+		"client.acme.match.weekdays": "mon,fri",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range set.rules {
+		names = append(names, r.name)
+	}
+	if want := []string{"alpha", "client.acme", "zeta"}; !slices.Equal(names, want) {
+		t.Errorf("rule names = %q, want %q", names, want)
+	}
+	if got := set.rules[0].category; got != "meetings" {
+		t.Errorf("set.category = %q, want it lowercased", got)
+	}
+	if got := set.rules[1].weekdays; !slices.Equal(got, []time.Weekday{time.Monday, time.Friday}) {
+		t.Errorf("match.weekdays = %v, want [Monday Friday]", got)
+	}
+
+	set, err = FromConfig(config(map[string]string{
+		"reports.lume.rules": "zeta, alpha",
+		"alpha.set.tags":     "a",
+		"zeta.set.tags":      "z",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.rules[0].name != "zeta" || set.rules[1].name != "alpha" {
+		t.Errorf("reports.lume.rules order not kept: %q, %q", set.rules[0].name, set.rules[1].name)
+	}
+}
+
+func TestFromConfigErrors(t *testing.T) {
+	tests := []struct {
+		values map[string]string
+		want   string
+	}{
+		{map[string]string{"r.match.tags": "dev"}, `rule "r" has no set.category, set.project or set.tags`},
+		{map[string]string{"r.match.description": "(", "r.set.tags": "x"}, "invalid reports.lume.rule.r.match.description"},
+		{map[string]string{"r.match.weekdays": "mon,someday", "r.set.tags": "x"}, `invalid reports.lume.rule.r.match.weekdays "someday"`},
+		{map[string]string{"r.match.time": "9-12", "r.set.tags": "x"}, `invalid reports.lume.rule.r.match.time "9-12"`},
+		{map[string]string{"r.set.categry": "x"}, `rule "r" has no set.category`},
+	}
+	for _, tt := range tests {
+		_, err := FromConfig(config(tt.values))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("FromConfig(%v) error = %v, want it to contain %q", tt.values, err, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	monday := func(h, m int) time.Time { return time.Date(2025, time.March, 10, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		name   string
+		values map[string]string
+		entry  timewarrior.Entry
+		want   []string
+	}{
+		{
+			name:   "description regex",
+			values: map[string]string{"r.match.description": "(?i)stand-?up", "r.set.category": "meetings"},
+			entry:  timewarrior.Entry{Description: "Daily Standup"},
+			want:   []string{"meetings"},
+		},
+		{
+			name:   "description regex misses",
+			values: map[string]string{"r.match.description": "(?i)stand-?up", "r.set.category": "meetings"},
+			entry:  timewarrior.Entry{Description: "Planning"},
+			want:   nil,
+		},
+		{
+			name:   "every tag must match",
+			values: map[string]string{"r.match.tags": "pr,acme", "r.set.category": "dev"},
+			entry:  timewarrior.Entry{Tags: []string{"pr"}},
+			want:   []string{"pr"},
+		},
+		{
+			name:   "existing category is kept",
+			values: map[string]string{"r.match.tags": "pr", "r.set.category": "dev"},
+			entry:  timewarrior.Entry{Tags: []string{"pr", "meetings"}},
+			want:   []string{"pr", "meetings"},
+		},
+		{
+			name:   "existing project is kept",
+			values: map[string]string{"r.set.project": "acme"},
+			entry:  timewarrior.Entry{Tags: []string{"project:globex"}},
+			want:   []string{"project:globex"},
+		},
+		{
+			name:   "project is set",
+			values: map[string]string{"r.set.project": "acme"},
+			entry:  timewarrior.Entry{Tags: []string{"dev"}},
+			want:   []string{"dev", "project:acme"},
+		},
+		{
+			name:   "set.tags adds missing tags only",
+			values: map[string]string{"r.set.tags": "billable, review"},
+			entry:  timewarrior.Entry{Tags: []string{"review"}},
+			want:   []string{"review", "billable"},
+		},
+		{
+			name:   "weekday",
+			values: map[string]string{"r.match.weekdays": "tue", "r.set.tags": "x"},
+			entry:  timewarrior.Entry{Start: monday(9, 0)},
+			want:   nil,
+		},
+		{
+			name:   "time window includes its start",
+			values: map[string]string{"r.match.time": "09:00-12:00", "r.set.tags": "x"},
+			entry:  timewarrior.Entry{Start: monday(9, 0)},
+			want:   []string{"x"},
+		},
+		{
+			name:   "time window excludes its end",
+			values: map[string]string{"r.match.time": "09:00-12:00", "r.set.tags": "x"},
+			entry:  timewarrior.Entry{Start: monday(12, 0)},
+			want:   nil,
+		},
+		{
+			name:   "time window wrapping midnight, late",
+			values: map[string]string{"r.match.time": "22:00-02:00", "r.set.tags": "night"},
+			entry:  timewarrior.Entry{Start: monday(23, 30)},
+			want:   []string{"night"},
+		},
+		{
+			name:   "time window wrapping midnight, early",
+			values: map[string]string{"r.match.time": "22:00-02:00", "r.set.tags": "night"},
+			entry:  timewarrior.Entry{Start: monday(1, 59)},
+			want:   []string{"night"},
+		},
+		{
+			name:   "time window wrapping midnight, outside",
+			values: map[string]string{"r.match.time": "22:00-02:00", "r.set.tags": "night"},
+			entry:  timewarrior.Entry{Start: monday(2, 0)},
+			want:   nil,
+		},
+		{
+			name: "later rules see earlier rules' tags",
+			values: map[string]string{
+				"a.match.description": "deploy", "a.set.tags": "ops",
+				"b.match.tags": "ops", "b.set.category": "dev",
+			},
+			entry: timewarrior.Entry{Description: "deploy"},
+			want:  []string{"ops", "dev"},
+		},
+		{
+			name: "first category wins",
+			values: map[string]string{
+				"a.set.category": "meetings",
+				"b.set.category": "dev",
+			},
+			entry: timewarrior.Entry{},
+			want:  []string{"meetings"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := FromConfig(config(tt.values))
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := slices.Clone(tt.entry.Tags)
+			got := set.Apply(tt.entry)
+			if !slices.Equal(got.Tags, tt.want) {
+				t.Errorf("Apply tags = %q, want %q", got.Tags, tt.want)
+			}
+			if !slices.Equal(tt.entry.Tags, original) {
+				t.Errorf("Apply changed the caller's tags to %q", tt.entry.Tags)
+			}
+		})
+	}
+}
+
+func TestApplyNilSet(t *testing.T) {
+	var set *Set
+	e := timewarrior.Entry{Tags: []string{"dev"}}
+	if got := set.Apply(e); !slices.Equal(got.Tags, e.Tags) {
+		t.Errorf("nil Set Apply = %q, want %q", got.Tags, e.Tags)
+	}
+}
+
+func TestMatchProject(t *testing.T) {
+	r := rule{project: "acme"}
+	tests := []struct {
+		project string
+		want    bool
+	}{
+		{"acme", true},
+		{"acme.api", true},
+		{"acme/api", true},
+		{"acme/api.v2", true},
+		{"acmecorp", false},
+		{"acme-api", false},
+		{"globex.acme", false},
+		{"globex/acme", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		e := timewarrior.Entry{}
+		if tt.project != "" {
+			e.Tags = []string{"project:" + tt.project}
+		}
+		if got := r.matches(e); got != tt.want {
+			t.Errorf("match.project = acme on %q: got %v, want %v", tt.project, got, tt.want)
+		}
+	}
+}
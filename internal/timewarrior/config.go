@@ -0,0 +1,181 @@
+package timewarrior
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadConfig reads the user's timewarrior.cfg the way timew locates it and
+// records the database directory under temp.db, as timew does in the header
+// it sends to extensions. A missing config file is not an error.
+func LoadConfig() (TimewConfig, error) {
+	dbDir := DatabaseDir()
+	if dbDir == "" {
+		return TimewConfig{}, fmt.Errorf("cannot determine timewarrior database directory")
+	}
+
+	cfg := TimewConfig{Values: make(map[string]string)}
+	path := filepath.Join(dbDir, "timewarrior.cfg")
+	if _, err := os.Stat(path); err == nil {
+		parsed, err := ParseConfigFile(path)
+		if err != nil {
+			return TimewConfig{}, err
+		}
+		cfg = parsed
+	} else if !os.IsNotExist(err) {
+		return TimewConfig{}, err
+	}
+
+	cfg.Values["temp.db"] = dbDir
+	return cfg, nil
+}
+
+// DatabaseDir resolves timewarrior's database directory: $TIMEWARRIORDB when
+// set, then the legacy ~/.timewarrior if it exists, then
+// ~/.config/timewarrior.
+func DatabaseDir() string {
+	if db := os.Getenv("TIMEWARRIORDB"); db != "" {
+		return db
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	legacy := filepath.Join(home, ".timewarrior")
+	if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+		return legacy
+	}
+	return filepath.Join(home, ".config", "timewarrior")
+}
+
+// ParseConfigFile parses a timewarrior.cfg into the flat dotted-key map timew
+// itself produces, so values read here match those in the stdin header.
+//
+// It understands "key = value" lines with dotted keys, "import <file>"
+// (relative to the importing file, "~" expanded), and hierarchical sections
+// opened by "name:" or "define name:" whose indented children are prefixed
+// with the section name. An unquoted "#" that starts the line or follows
+// whitespace begins a comment; one inside a word, as in a rule's regular
+// expression, is part of the value.
+func ParseConfigFile(path string) (TimewConfig, error) {
+	cfg := TimewConfig{Values: make(map[string]string)}
+	if err := parseConfigInto(cfg.Values, path, make(map[string]bool)); err != nil {
+		return TimewConfig{}, err
+	}
+	return cfg, nil
+}
+
+type configSection struct {
+	indent int
+	prefix string
+}
+
+func parseConfigInto(values map[string]string, path string, seen map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if seen[abs] {
+		return fmt.Errorf("%s: circular import", path)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	file, err := os.Open(abs)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var sections []configSection
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		raw := stripConfigComment(scanner.Text())
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		for len(sections) > 0 && indent <= sections[len(sections)-1].indent {
+			sections = sections[:len(sections)-1]
+		}
+		prefix := ""
+		if len(sections) > 0 {
+			prefix = sections[len(sections)-1].prefix
+		}
+
+		if target, ok := strings.CutPrefix(line, "import "); ok {
+			target = expandHome(unquoteConfigValue(strings.TrimSpace(target)))
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(abs), target)
+			}
+			if err := parseConfigInto(values, target, seen); err != nil {
+				return fmt.Errorf("%s:%d: %w", path, lineNum, err)
+			}
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, "="); ok {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				return fmt.Errorf("%s:%d: missing key before '='", path, lineNum)
+			}
+			values[prefix+key] = unquoteConfigValue(strings.TrimSpace(value))
+			continue
+		}
+
+		if name, ok := strings.CutSuffix(line, ":"); ok {
+			name = strings.TrimSpace(strings.TrimPrefix(name, "define "))
+			if name == "" {
+				return fmt.Errorf("%s:%d: empty section name", path, lineNum)
+			}
+			sections = append(sections, configSection{indent: indent, prefix: prefix + name + "."})
+			continue
+		}
+
+		return fmt.Errorf("%s:%d: cannot parse %q", path, lineNum, line)
+	}
+	return scanner.Err()
+}
+
+// stripConfigComment drops everything from the first "#" that is outside
+// double quotes and starts the line or follows a space or tab.
+func stripConfigComment(line string) string {
+	inQuotes := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case '#':
+			if !inQuotes && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+func unquoteConfigValue(v string) string {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/') {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + rest
+}
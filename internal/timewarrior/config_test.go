@@ -0,0 +1,169 @@
+package timewarrior
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes each named file under dir and returns dir.
+func writeConfig(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseConfigFile(t *testing.T) {
+	dir := writeConfig(t, map[string]string{
+		"timewarrior.cfg": `# full-line comment
+reports.lume.format = markdown   # trailing comment
+reports.lume.rule.issue.match.description = (?i)issue#\d+
+reports.lume.filter = "desc~standup # daily"
+quoted = "  padded  "
+empty =
+import extra/more.cfg
+
+define reports:
+  lume:
+    birthday = 01-15
+    billing:
+      rate = 90
+  week:
+    start = monday
+theme.colors.today = "white on red"
+reports.day.hours = auto
+`,
+		"extra/more.cfg": `reports.lume.categories = dev, ops
+import ../last.cfg
+`,
+		"last.cfg": "reports.lume.weekstart = sunday\n",
+	})
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "timewarrior.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"reports.lume.format":                       "markdown",
+		"reports.lume.rule.issue.match.description": `(?i)issue#\d+`,
+		"reports.lume.filter":                       "desc~standup # daily",
+		"quoted":                                    "  padded  ",
+		"empty":                                     "",
+		"reports.lume.categories":                   "dev, ops",
+		"reports.lume.weekstart":                    "sunday",
+		"reports.lume.birthday":                     "01-15",
+		"reports.lume.billing.rate":                 "90",
+		"reports.week.start":                        "monday",
+		"theme.colors.today":                        "white on red",
+		"reports.day.hours":                         "auto",
+	}
+	for key, value := range want {
+		if got, ok := cfg.Values[key]; !ok || got != value {
+			t.Errorf("%s = %q (set %v), want %q", key, got, ok, value)
+		}
+	}
+	if len(cfg.Values) != len(want) {
+		t.Errorf("parsed %d keys, want %d: %v", len(cfg.Values), len(want), cfg.Values)
+	}
+}
+
+func TestParseConfigFileImportHome(t *testing.T) {
+	home := writeConfig(t, map[string]string{"shared.cfg": "reports.lume.format = json\n"})
+	t.Setenv("HOME", home)
+	dir := writeConfig(t, map[string]string{"timewarrior.cfg": "import ~/shared.cfg\n"})
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "timewarrior.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Values["reports.lume.format"]; got != "json" {
+		t.Errorf("reports.lume.format = %q, want json from the imported file", got)
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"circular import", map[string]string{
+			"timewarrior.cfg": "a = 1\nimport b.cfg\n",
+			"b.cfg":           "import timewarrior.cfg\n",
+		}, "timewarrior.cfg:2: " + "DIR/b.cfg:1: DIR/timewarrior.cfg: circular import"},
+		{"self import", map[string]string{
+			"timewarrior.cfg": "import ./timewarrior.cfg\n",
+		}, "timewarrior.cfg:1: DIR/timewarrior.cfg: circular import"},
+		{"missing import", map[string]string{
+			"timewarrior.cfg": "import missing.cfg\n",
+		}, "timewarrior.cfg:1: open DIR/missing.cfg: no such file or directory"},
+		{"missing key", map[string]string{
+			"timewarrior.cfg": "  = value\n",
+		}, "timewarrior.cfg:1: missing key before '='"},
+		{"empty section", map[string]string{
+			"timewarrior.cfg": "define :\n",
+		}, "timewarrior.cfg:1: empty section name"},
+		{"unparsable line", map[string]string{
+			"timewarrior.cfg": "a = 1\njust words\n",
+		}, `timewarrior.cfg:2: cannot parse "just words"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeConfig(t, tt.files)
+			_, err := ParseConfigFile(filepath.Join(dir, "timewarrior.cfg"))
+			want := strings.ReplaceAll(tt.want, "DIR", dir)
+			if err == nil || !strings.HasSuffix(err.Error(), want) {
+				t.Errorf("error = %v, want it to end with %q", err, want)
+			}
+		})
+	}
+}
+
+func TestStripConfigComment(t *testing.T) {
+	tests := []struct{ line, want string }{
+		{"# comment", ""},
+		{"a = 1 # comment", "a = 1 "},
+		{"a = 1\t# comment", "a = 1\t"},
+		{"a = issue#12", "a = issue#12"},
+		{`a = "x # y" # z`, `a = "x # y" `},
+		{"a = #", "a = "},
+	}
+	for _, tt := range tests {
+		if got := stripConfigComment(tt.line); got != tt.want {
+			t.Errorf("stripConfigComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Weekday
+		ok   bool
+	}{
+		{"monday", time.Monday, true},
+		{"Mon", time.Monday, true},
+		{" SUNDAY ", time.Sunday, true},
+		{"sat", time.Saturday, true},
+		{"mo", 0, false},
+		{"mond", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseWeekday(tt.s)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseWeekday(%q) = %v, %v, want %v, %v", tt.s, got, ok, tt.want, tt.ok)
+		}
+	}
+}
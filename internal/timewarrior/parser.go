@@ -4,7 +4,7 @@ import (
 	"bufio"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -14,13 +14,46 @@ type Entry struct {
 	End         time.Time
 	Description string
 	Tags        []string
+	Annotation  string
 }
 
 func (e Entry) Duration() time.Duration {
 	return e.End.Sub(e.Start)
 }
 
-var entryRegex = regexp.MustCompile(`^inc (\d{8}T\d{6}Z) - (\d{8}T\d{6}Z) # (.*)$`)
+// Project returns the name in e's first non-empty project:<name> tag, or ""
+// when it has none.
+func (e Entry) Project() string {
+	for _, tag := range e.Tags {
+		if name, ok := strings.CutPrefix(tag, "project:"); ok && name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// InProject reports whether e's project is name or one of its sub-projects,
+// which are separated by "." or "/" (acme.api and acme/api are in acme).
+func (e Entry) InProject(name string) bool {
+	project := e.Project()
+	return project == name || strings.HasPrefix(project, name+".") || strings.HasPrefix(project, name+"/")
+}
+
+// HasTags reports whether e carries every one of tags, which is how timew
+// applies a tag filter. A "desc:..." tag, which parsing moves into
+// Description, matches the entry's description.
+func (e Entry) HasTags(tags []string) bool {
+	for _, want := range tags {
+		if slices.Contains(e.Tags, want) {
+			continue
+		}
+		if desc, ok := strings.CutPrefix(want, "desc:"); ok && desc != "" && desc == e.Description {
+			continue
+		}
+		return false
+	}
+	return true
+}
 
 func ParseDataDir(dataDir string) ([]Entry, error) {
 	files, err := filepath.Glob(filepath.Join(dataDir, "*.data"))
@@ -60,78 +93,114 @@ func parseFile(path string) ([]Entry, error) {
 	return entries, scanner.Err()
 }
 
+// parseLine parses one interval line of a timewarrior data file:
+//
+//	inc START [- END] [# TAG ...] [# "ANNOTATION"]
+//
+// Tags containing spaces or quotes are double-quoted with backslash escapes.
+// timew quotes annotations the same way, so a bare "#" after the annotation
+// marker cannot be part of it and the line is rejected as malformed.
+// An open interval (no END) runs until now, as in timew's JSON export; when
+// there are no tags but an annotation, timew writes an empty "# #" section.
 func parseLine(line string) (Entry, bool) {
-	matches := entryRegex.FindStringSubmatch(line)
-	if matches == nil {
+	tokens, ok := tokenizeDataLine(line)
+	if !ok || len(tokens) < 2 || tokens[0].text != "inc" || tokens[0].quoted {
 		return Entry{}, false
 	}
 
-	start, err := time.Parse("20060102T150405Z", matches[1])
+	start, err := time.Parse("20060102T150405Z", tokens[1].text)
 	if err != nil {
 		return Entry{}, false
 	}
+	end := time.Now()
+	rest := tokens[2:]
+	if len(rest) >= 2 && rest[0].isMarker("-") {
+		end, err = time.Parse("20060102T150405Z", rest[1].text)
+		if err != nil {
+			return Entry{}, false
+		}
+		rest = rest[2:]
+	}
 
-	end, err := time.Parse("20060102T150405Z", matches[2])
-	if err != nil {
+	var rawTags []string
+	var annotation []string
+	section := 0
+	for _, tok := range rest {
+		if tok.isMarker("#") {
+			section++
+			continue
+		}
+		switch section {
+		case 1:
+			rawTags = append(rawTags, tok.text)
+		case 2:
+			annotation = append(annotation, tok.text)
+		default:
+			return Entry{}, false
+		}
+	}
+	if section > 2 {
 		return Entry{}, false
 	}
 
-	start = start.In(time.Local)
-	end = end.In(time.Local)
-
-	desc, tags := parseAnnotation(matches[3])
+	desc, tags := parseTimewTags(rawTags)
 
 	return Entry{
-		Start:       start,
-		End:         end,
+		Start:       start.In(time.Local),
+		End:         end.In(time.Local),
 		Description: desc,
 		Tags:        tags,
+		Annotation:  strings.Join(annotation, " "),
 	}, true
 }
 
-func parseAnnotation(annotation string) (string, []string) {
-	var description string
-	var tags []string
-
-	parts := splitAnnotation(annotation)
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		part = strings.Trim(part, "\"")
-		if strings.HasPrefix(part, "desc:") {
-			description = part[5:]
-		} else if part != "" && !strings.HasPrefix(part, "#") {
-			tags = append(tags, part)
-		}
-	}
+type dataToken struct {
+	text   string
+	quoted bool
+}
 
-	return description, tags
+// isMarker reports whether tok is the bare syntax marker s ("-" or "#"), as
+// opposed to a quoted tag that happens to read the same.
+func (tok dataToken) isMarker(s string) bool {
+	return !tok.quoted && tok.text == s
 }
 
-func splitAnnotation(annotation string) []string {
-	var parts []string
+// tokenizeDataLine splits a data file line on whitespace, keeping
+// double-quoted runs together and resolving backslash escapes inside them.
+// ok is false for an unterminated quote.
+func tokenizeDataLine(line string) ([]dataToken, bool) {
+	var tokens []dataToken
 	var current strings.Builder
-	inQuotes := false
+	inToken, inQuotes, quoted := false, false, false
 
-	for _, r := range annotation {
-		switch r {
-		case '"':
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, dataToken{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		inToken, quoted = false, false
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inQuotes && r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+		case r == '"':
 			inQuotes = !inQuotes
-			current.WriteRune(r)
-		case ' ':
-			if inQuotes {
-				current.WriteRune(r)
-			} else {
-				if current.Len() > 0 {
-					parts = append(parts, current.String())
-					current.Reset()
-				}
-			}
+			inToken, quoted = true, true
+		case !inQuotes && (r == ' ' || r == '\t'):
+			flush()
 		default:
+			inToken = true
 			current.WriteRune(r)
 		}
 	}
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
+	if inQuotes {
+		return nil, false
 	}
-	return parts
+	flush()
+	return tokens, true
 }
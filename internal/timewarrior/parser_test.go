@@ -0,0 +1,186 @@
+package timewarrior
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHasTags(t *testing.T) {
+	e := Entry{Description: "Fix login", Tags: []string{"dev", "project:acme"}}
+	tests := []struct {
+		tags []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"dev"}, true},
+		{[]string{"dev", "project:acme"}, true},
+		{[]string{"dev", "ops"}, false},
+		{[]string{"desc:Fix login"}, true},
+		{[]string{"desc:Fix login", "dev"}, true},
+		{[]string{"desc:Fix"}, false},
+		{[]string{"desc:"}, false},
+	}
+	for _, tt := range tests {
+		if got := e.HasTags(tt.tags); got != tt.want {
+			t.Errorf("HasTags(%q) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		project string
+		inAcme  bool
+	}{
+		{nil, "", false},
+		{[]string{"dev", "project:acme"}, "acme", true},
+		{[]string{"project:", "project:acme.api", "project:globex"}, "acme.api", true},
+		{[]string{"project:acme/web"}, "acme/web", true},
+		{[]string{"project:acmecorp"}, "acmecorp", false},
+		{[]string{"project:globex.acme"}, "globex.acme", false},
+	}
+	for _, tt := range tests {
+		e := Entry{Tags: tt.tags}
+		if got := e.Project(); got != tt.project {
+			t.Errorf("Project() of %q = %q, want %q", tt.tags, got, tt.project)
+		}
+		if got := e.InProject("acme"); got != tt.inAcme {
+			t.Errorf("InProject(acme) of %q = %v, want %v", tt.tags, got, tt.inAcme)
+		}
+	}
+}
+
+func TestTokenizeDataLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		tokens []dataToken
+		ok     bool
+	}{
+		{"bare words", "inc 20250310T090000Z  -\t20250310T100000Z", []dataToken{
+			{text: "inc"}, {text: "20250310T090000Z"}, {text: "-"}, {text: "20250310T100000Z"},
+		}, true},
+		{"quoted run", `# dev "desc:Fix login"`, []dataToken{
+			{text: "#"}, {text: "dev"}, {text: "desc:Fix login", quoted: true},
+		}, true},
+		{"escaped quotes", `# "say \"hi\"" "back\\slash"`, []dataToken{
+			{text: "#"}, {text: `say "hi"`, quoted: true}, {text: `back\slash`, quoted: true},
+		}, true},
+		{"quoted markers", `"#" "-"`, []dataToken{
+			{text: "#", quoted: true}, {text: "-", quoted: true},
+		}, true},
+		{"empty quotes", `# ""`, []dataToken{{text: "#"}, {text: "", quoted: true}}, true},
+		{"unterminated quote", `# "desc:Fix login`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, ok := tokenizeDataLine(tt.line)
+			if ok != tt.ok || !reflect.DeepEqual(tokens, tt.tokens) {
+				t.Errorf("tokenizeDataLine(%q) = %#v, %v, want %#v, %v", tt.line, tokens, ok, tt.tokens, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("20060102T150405Z", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	start, end := at("20250310T090000Z"), at("20250310T100000Z")
+	tests := []struct {
+		name string
+		line string
+		want Entry
+	}{
+		{"tags and description", `inc 20250310T090000Z - 20250310T100000Z # dev project:acme "desc:Fix login"`,
+			Entry{Start: start, End: end, Description: "Fix login", Tags: []string{"dev", "project:acme"}}},
+		{"no tags", "inc 20250310T090000Z - 20250310T100000Z",
+			Entry{Start: start, End: end}},
+		{"escaped quotes", `inc 20250310T090000Z - 20250310T100000Z # "desc:Say \"hi\"" "code \"review\""`,
+			Entry{Start: start, End: end, Description: `Say "hi"`, Tags: []string{`code "review"`}}},
+		{"quoted marker tags", `inc 20250310T090000Z - 20250310T100000Z # "#" "-" dev`,
+			Entry{Start: start, End: end, Tags: []string{"#", "-", "dev"}}},
+		{"annotation", `inc 20250310T090000Z - 20250310T100000Z # dev # "ticket 12"`,
+			Entry{Start: start, End: end, Tags: []string{"dev"}, Annotation: "ticket 12"}},
+		{"annotation only", `inc 20250310T090000Z - 20250310T100000Z # # "ticket 12"`,
+			Entry{Start: start, End: end, Annotation: "ticket 12"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLine(tt.line)
+			if !ok {
+				t.Fatalf("parseLine(%q) failed", tt.line)
+			}
+			if !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("parseLine(%q) spans %v - %v, want %v - %v", tt.line, got.Start, got.End, tt.want.Start, tt.want.End)
+			}
+			got.Start, got.End, tt.want.Start, tt.want.End = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLine(%q) = %#v, want %#v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineOpenInterval(t *testing.T) {
+	before := time.Now()
+	got, ok := parseLine("inc 20250310T090000Z # dev")
+	after := time.Now()
+	if !ok {
+		t.Fatal("parseLine failed on an open interval")
+	}
+	if got.End.Before(before) || got.End.After(after) {
+		t.Errorf("open interval ends at %v, want now (%v)", got.End, before)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "dev" {
+		t.Errorf("Tags = %q, want [dev]", got.Tags)
+	}
+}
+
+// TestParseLineAnnotationHash documents how a "#" inside an annotation is
+// read: timew quotes an annotation containing spaces, so only a quoted one or
+// a "#" inside a word belongs to it; a bare "#" would start a fourth section
+// and the line is rejected.
+func TestParseLineAnnotationHash(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC).In(time.Local)
+	end := start.Add(time.Hour)
+	tests := []struct {
+		line string
+		want Entry
+		ok   bool
+	}{
+		{`inc 20250310T090000Z - 20250310T100000Z # dev # "see # 12"`,
+			Entry{Start: start, End: end, Tags: []string{"dev"}, Annotation: "see # 12"}, true},
+		{`inc 20250310T090000Z - 20250310T100000Z # dev # see #12`,
+			Entry{Start: start, End: end, Tags: []string{"dev"}, Annotation: "see #12"}, true},
+		{`inc 20250310T090000Z - 20250310T100000Z # dev # see # 12`, Entry{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseLine(tt.line)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLine(%q) = %#v, %t, want %#v, %t", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseLineRejects(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"exc 20250310T090000Z - 20250310T100000Z",
+		`"inc" 20250310T090000Z - 20250310T100000Z`,
+		"inc yesterday",
+		"inc 20250310T090000Z - later",
+		"inc 20250310T090000Z - 20250310T100000Z dev",
+		`inc 20250310T090000Z - 20250310T100000Z # "desc:Fix login`,
+	} {
+		if got, ok := parseLine(line); ok {
+			t.Errorf("parseLine(%q) = %#v, want rejected", line, got)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -50,12 +52,55 @@ func (c TimewConfig) HasTag(tag string) bool {
 	return v == tag
 }
 
+// ReportTags returns the tag filter timew applied to the report, parsed from
+// the comma-separated temp.report.tags value. Tags with spaces arrive quoted.
+func (c TimewConfig) ReportTags() []string {
+	var tags []string
+	for _, t := range strings.Split(c.Values["temp.report.tags"], ",") {
+		t = strings.Trim(strings.TrimSpace(t), "\"")
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
 // Format returns the configured output format from reports.lume.format.
 // Empty string means unset; the caller applies its own default and precedence.
 func (c TimewConfig) Format() string {
 	return strings.TrimSpace(c.Values["reports.lume.format"])
 }
 
+// Pivot returns the pivot axes from reports.lume.pivot, as "rows,columns".
+// Empty string means unset; the caller applies its own precedence.
+func (c TimewConfig) Pivot() string {
+	return strings.TrimSpace(c.Values["reports.lume.pivot"])
+}
+
+// Report returns the report kind from reports.lume.report (e.g. "billing").
+// Empty string means unset; the caller applies its own precedence.
+func (c TimewConfig) Report() string {
+	return strings.TrimSpace(c.Values["reports.lume.report"])
+}
+
+// Filter returns the report filter expression from reports.lume.filter.
+// Empty string means unset; the caller applies its own precedence.
+func (c TimewConfig) Filter() string {
+	return strings.TrimSpace(c.Values["reports.lume.filter"])
+}
+
+// Charts returns the comma-separated chart names from reports.lume.charts.
+// Empty string means unset; the caller applies its own precedence.
+func (c TimewConfig) Charts() string {
+	return strings.TrimSpace(c.Values["reports.lume.charts"])
+}
+
+// ChartsDir returns the directory charts are written to, from
+// reports.lume.charts.dir with "~" expanded. Empty string means unset.
+func (c TimewConfig) ChartsDir() string {
+	return expandHome(strings.TrimSpace(c.Values["reports.lume.charts.dir"]))
+}
+
 func (c TimewConfig) Birthday() (time.Month, int, error) {
 	v := strings.TrimSpace(c.Values["reports.lume.birthday"])
 	if v == "" {
@@ -73,10 +118,178 @@ func (c TimewConfig) Birthday() (time.Month, int, error) {
 	return 0, 0, fmt.Errorf("invalid reports.lume.birthday %q (use MM-DD or YYYY-MM-DD)", v)
 }
 
+// Categories returns the ordered task categories from reports.lume.categories
+// (comma-separated) and the bucket for tasks matching none of them from
+// reports.lume.categories.fallback. The fallback defaults to "misc", is
+// appended to the list when missing from it, and "none" disables it.
+// Without configuration the categories are dev, meetings, knowledge, misc.
+func (c TimewConfig) Categories() ([]string, string) {
+	var categories []string
+	for _, name := range strings.Split(c.Values["reports.lume.categories"], ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(categories, name) {
+			categories = append(categories, name)
+		}
+	}
+	if len(categories) == 0 {
+		categories = []string{"dev", "meetings", "knowledge", "misc"}
+	}
+
+	fallback := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.categories.fallback"]))
+	switch fallback {
+	case "":
+		fallback = "misc"
+	case "none":
+		return categories, ""
+	}
+	if !slices.Contains(categories, fallback) {
+		categories = append(categories, fallback)
+	}
+	return categories, fallback
+}
+
+// Dimensions returns the key:value tag keys listed in reports.lume.dimensions,
+// such as client or env, that reports give a share table of their own.
+func (c TimewConfig) Dimensions() []string {
+	var dimensions []string
+	for _, name := range strings.Split(c.Values["reports.lume.dimensions"], ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && name != "project" && !slices.Contains(dimensions, name) {
+			dimensions = append(dimensions, name)
+		}
+	}
+	return dimensions
+}
+
+// Allocation returns how reports.lume.allocation shares out an entry with
+// several category tags: "full" (the default) counts it under each, "split"
+// divides it evenly and "primary" gives it to a single tag.
+func (c TimewConfig) Allocation() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.allocation"]))
+	switch v {
+	case "":
+		return "full", nil
+	case "full", "split", "primary":
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid reports.lume.allocation %q (use full, split or primary)", v)
+}
+
+// Rounding returns how reports.lume.rounding shows durations in whole
+// minutes: "balanced" (the default) rounds to the nearest minute and keeps
+// table rows adding up to their total, "nearest", "down" and "up" round each
+// value on its own.
+func (c TimewConfig) Rounding() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.rounding"]))
+	switch v {
+	case "":
+		return "balanced", nil
+	case "balanced", "nearest", "down", "up":
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid reports.lume.rounding %q (use balanced, nearest, down or up)", v)
+}
+
+// ExportRows returns what CSV and TSV output lists, from
+// reports.lume.export.rows: "tasks" (the default) for the aggregated task
+// rows, or "sessions" for one row per interval.
+func (c TimewConfig) ExportRows() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.export.rows"]))
+	switch v {
+	case "":
+		return "tasks", nil
+	case "tasks", "sessions":
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid reports.lume.export.rows %q (use tasks or sessions)", v)
+}
+
+// ExportHours returns how CSV and TSV output writes durations, from
+// reports.lume.export.hours: "decimal" (the default, 1.25) or "h:mm" (1:15).
+func (c TimewConfig) ExportHours() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.export.hours"]))
+	switch v {
+	case "":
+		return "decimal", nil
+	case "decimal", "h:mm":
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid reports.lume.export.hours %q (use decimal or h:mm)", v)
+}
+
+// ProjectDepth returns how many levels of dotted project names
+// reports.lume.project.depth shows; 0 (the default) shows every level.
+func (c TimewConfig) ProjectDepth() (int, error) {
+	v := strings.TrimSpace(c.Values["reports.lume.project.depth"])
+	if v == "" {
+		return 0, nil
+	}
+	depth, err := strconv.Atoi(v)
+	if err != nil || depth < 0 {
+		return 0, fmt.Errorf("invalid reports.lume.project.depth %q (use a non-negative number)", v)
+	}
+	return depth, nil
+}
+
+// ISOWeeks reports whether reports.lume.weeknumbers selects ISO 8601 week
+// numbers ("iso") over the default count from the birthday ("birthday").
+func (c TimewConfig) ISOWeeks() (bool, error) {
+	v := strings.ToLower(strings.TrimSpace(c.Values["reports.lume.weeknumbers"]))
+	switch v {
+	case "", "birthday":
+		return false, nil
+	case "iso":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid reports.lume.weeknumbers %q (use birthday or iso)", v)
+}
+
+// WeekStart returns the first day of the week from reports.lume.weekstart,
+// falling back to timew's reports.week.start. When neither is set, weeks
+// start on Monday with ISO week numbers and on Sunday otherwise.
+func (c TimewConfig) WeekStart() (time.Weekday, error) {
+	for _, key := range []string{"reports.lume.weekstart", "reports.week.start"} {
+		v := strings.TrimSpace(c.Values[key])
+		if v == "" {
+			continue
+		}
+		day, ok := ParseWeekday(v)
+		if !ok {
+			return 0, fmt.Errorf("invalid %s %q (use a weekday such as monday)", key, v)
+		}
+		return day, nil
+	}
+
+	iso, err := c.ISOWeeks()
+	if err != nil {
+		return 0, err
+	}
+	if iso {
+		return time.Monday, nil
+	}
+	return time.Sunday, nil
+}
+
+// ParseWeekday accepts full or three-letter English weekday names in any case.
+func ParseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
 type timewInterval struct {
-	Start string   `json:"start"`
-	End   string   `json:"end"`
-	Tags  []string `json:"tags"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
 }
 
 func ParseStdin(r io.Reader) (TimewConfig, []Entry, error) {
@@ -139,6 +352,7 @@ func ParseStdin(r io.Reader) (TimewConfig, []Entry, error) {
 			End:         end,
 			Description: desc,
 			Tags:        tags,
+			Annotation:  iv.Annotation,
 		})
 	}
 
@@ -4,14 +4,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/amiraminb/lume/internal/filter"
+	"github.com/amiraminb/lume/internal/report/billing"
 	"github.com/amiraminb/lume/internal/report/build"
+	"github.com/amiraminb/lume/internal/report/civil"
 	"github.com/amiraminb/lume/internal/report/render"
+	"github.com/amiraminb/lume/internal/report/rules"
 	"github.com/amiraminb/lume/internal/timewarrior"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runStandalone(os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "lume: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// timew always pipes its config and export into an extension; a terminal
+	// on stdin means lume was started by hand without a command.
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
 	cfg, entries, err := timewarrior.ParseStdin(os.Stdin)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "lume: %v\n", err)
@@ -25,16 +46,100 @@ func main() {
 }
 
 func runReport(cfg timewarrior.TimewConfig, entries []timewarrior.Entry) error {
-	birthdayMonth, birthdayDay, err := cfg.Birthday()
+	buildOpts, renderOpts, err := reportOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	match, err := resolveFilter(cfg)
 	if err != nil {
 		return err
 	}
+	entries = filter.Apply(match, entries, buildOpts.Rules.Apply)
 
 	format := resolveFormat(cfg)
+	if format == formatPDF {
+		// A PDF is binary; refuse to dump it on a terminal.
+		if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return fmt.Errorf("pdf output is binary; redirect it to a file (lume report :lastweek > timesheet.pdf)")
+		}
+	}
+
+	var charts chartFiles
+	if format == formatSVG || format == formatPNG {
+		if charts, err = resolveCharts(cfg, format, renderOpts); err != nil {
+			return err
+		}
+	}
 
 	start, hasStart := cfg.ReportStart()
 	end, hasEnd := cfg.ReportEnd()
 
+	kind, err := resolveReportKind(cfg)
+	if err != nil {
+		return err
+	}
+	if kind == reportBilling {
+		if format == formatJSON || format == formatHTML || format == formatSVG || format == formatPNG {
+			return fmt.Errorf("%s output is not available for the billing report", format)
+		}
+		if buildOpts.Billing == nil {
+			return fmt.Errorf("billing report needs a billing policy (set reports.lume.billing.rate)")
+		}
+		if !hasStart || !hasEnd {
+			start, end = time.Time{}, time.Time{}
+		}
+		data := build.BillingReport(entries, start, end, buildOpts)
+		switch format {
+		case formatColor:
+			render.BillingReportANSI(os.Stdout, data, renderOpts)
+		case formatCSV, formatTSV:
+			return render.BillingReportCSV(os.Stdout, data, delimiter(format), renderOpts)
+		case formatPDF:
+			return render.InvoicePDF(os.Stdout, data, renderOpts)
+		default:
+			render.BillingReport(os.Stdout, data, renderOpts)
+		}
+		return nil
+	}
+
+	rowBy, columnBy, err := resolvePivot(cfg, entries, buildOpts)
+	if err != nil {
+		return err
+	}
+	if rowBy != "" {
+		if format == formatPDF || format == formatJSON || format == formatHTML || format == formatSVG || format == formatPNG {
+			return fmt.Errorf("%s output is not available for pivot reports", format)
+		}
+		if !hasStart || !hasEnd {
+			start, end = time.Time{}, time.Time{}
+		}
+		data := build.PivotReport(entries, start, end, rowBy, columnBy, buildOpts)
+		switch format {
+		case formatColor:
+			render.PivotReportANSI(os.Stdout, data, renderOpts)
+		case formatCSV, formatTSV:
+			return render.PivotReportCSV(os.Stdout, data, delimiter(format), renderOpts)
+		default:
+			render.PivotReport(os.Stdout, data, renderOpts)
+		}
+		return nil
+	}
+	if format == formatCSV || format == formatTSV {
+		rows, err := cfg.ExportRows()
+		if err != nil {
+			return err
+		}
+		if rows == "sessions" {
+			return render.SessionsCSV(os.Stdout, build.Sessions(entries, buildOpts), delimiter(format), renderOpts)
+		}
+		if !hasStart || !hasEnd {
+			start, end = time.Time{}, time.Time{}
+		}
+		data := build.RangeReport(entries, start, end, buildOpts)
+		return render.TasksCSV(os.Stdout, data, delimiter(format), renderOpts)
+	}
+
 	if !hasStart || !hasEnd {
 		if len(entries) == 0 {
 			fmt.Println("No entries found.")
@@ -50,61 +155,187 @@ func runReport(cfg timewarrior.TimewConfig, entries []timewarrior.Entry) error {
 				latest = e.End
 			}
 		}
-		data := build.RangeReport(entries, earliest, latest)
-		if format == formatColor {
-			render.RangeReportANSI(os.Stdout, data, earliest, latest, birthdayMonth, birthdayDay)
-		} else {
-			render.RangeReport(os.Stdout, data, earliest, latest, birthdayMonth, birthdayDay)
+		data := build.RangeReport(entries, earliest, latest, buildOpts)
+		switch format {
+		case formatPDF:
+			return render.TimesheetPDF(os.Stdout, data, earliest, latest, renderOpts)
+		case formatJSON:
+			return render.RangeReportJSON(os.Stdout, data, earliest, latest, renderOpts)
+		case formatHTML:
+			return render.RangeReportHTML(os.Stdout, data, earliest, latest, renderOpts)
+		case formatSVG, formatPNG:
+			return charts.write(render.MonthCharts(data, renderOpts))
+		case formatColor:
+			render.RangeReportANSI(os.Stdout, data, earliest, latest, renderOpts)
+		default:
+			render.RangeReport(os.Stdout, data, earliest, latest, renderOpts)
 		}
 		return nil
 	}
 
-	days := int(end.Sub(start).Hours()/24 + 0.5)
+	days := reportDays(start, end)
+
+	if format == formatPDF {
+		data := build.RangeReport(entries, start, end, buildOpts)
+		return render.TimesheetPDF(os.Stdout, data, start, end, renderOpts)
+	}
 
 	nextMonth := start.AddDate(0, 1, 0)
 	isFullMonth := start.Day() == 1 && end.Year() == nextMonth.Year() && end.Month() == nextMonth.Month()
 
 	switch {
 	case days <= 1:
-		data := build.DayReport(entries, start)
-		if format == formatColor {
-			render.DayReportANSI(os.Stdout, data, birthdayMonth, birthdayDay)
-		} else {
-			render.DayReport(os.Stdout, data, birthdayMonth, birthdayDay)
+		data := build.DayReport(entries, start, buildOpts)
+		switch format {
+		case formatJSON:
+			return render.DayReportJSON(os.Stdout, data, renderOpts)
+		case formatHTML:
+			return render.DayReportHTML(os.Stdout, data, renderOpts)
+		case formatSVG, formatPNG:
+			return charts.write(render.DayCharts(data, renderOpts))
+		case formatColor:
+			render.DayReportANSI(os.Stdout, data, renderOpts)
+		default:
+			render.DayReport(os.Stdout, data, renderOpts)
 		}
-	case days <= 7:
+	case days <= 7 && build.InOneWeek(start, end, buildOpts):
 		allEntries, err := loadAllEntries(cfg)
 		if err != nil {
 			return err
 		}
-		data := build.WeekReport(allEntries, start)
-		if format == formatColor {
-			render.WeekReportANSI(os.Stdout, data, birthdayMonth, birthdayDay)
-		} else {
-			render.WeekReport(os.Stdout, data, birthdayMonth, birthdayDay)
+		data := build.WeekReport(filter.Apply(match, allEntries, buildOpts.Rules.Apply), start, end, buildOpts)
+		switch format {
+		case formatJSON:
+			return render.WeekReportJSON(os.Stdout, data, start, end, renderOpts)
+		case formatHTML:
+			return render.WeekReportHTML(os.Stdout, data, renderOpts)
+		case formatSVG, formatPNG:
+			return charts.write(render.WeekCharts(data, renderOpts))
+		case formatColor:
+			render.WeekReportANSI(os.Stdout, data, renderOpts)
+		default:
+			render.WeekReport(os.Stdout, data, renderOpts)
 		}
 	case isFullMonth:
-		data := build.MonthReport(entries, start.Month(), start.Year())
-		if format == formatColor {
-			render.MonthReportANSI(os.Stdout, data, start.Year(), birthdayMonth, birthdayDay)
-		} else {
-			render.MonthReport(os.Stdout, data, start.Year(), birthdayMonth, birthdayDay)
+		data := build.MonthReport(entries, start.Month(), start.Year(), buildOpts)
+		switch format {
+		case formatJSON:
+			return render.MonthReportJSON(os.Stdout, data, start.Year(), renderOpts)
+		case formatHTML:
+			return render.MonthReportHTML(os.Stdout, data, start.Year(), renderOpts)
+		case formatSVG, formatPNG:
+			return charts.write(render.MonthCharts(data, renderOpts))
+		case formatColor:
+			render.MonthReportANSI(os.Stdout, data, start.Year(), renderOpts)
+		default:
+			render.MonthReport(os.Stdout, data, start.Year(), renderOpts)
 		}
 	default:
-		data := build.RangeReport(entries, start, end)
-		if format == formatColor {
-			render.RangeReportANSI(os.Stdout, data, start, end, birthdayMonth, birthdayDay)
-		} else {
-			render.RangeReport(os.Stdout, data, start, end, birthdayMonth, birthdayDay)
+		data := build.RangeReport(entries, start, end, buildOpts)
+		switch format {
+		case formatJSON:
+			return render.RangeReportJSON(os.Stdout, data, start, end, renderOpts)
+		case formatHTML:
+			return render.RangeReportHTML(os.Stdout, data, start, end, renderOpts)
+		case formatSVG, formatPNG:
+			return charts.write(render.MonthCharts(data, renderOpts))
+		case formatColor:
+			render.RangeReportANSI(os.Stdout, data, start, end, renderOpts)
+		default:
+			render.RangeReport(os.Stdout, data, start, end, renderOpts)
 		}
 	}
 
 	return nil
 }
 
+// reportDays counts the calendar days the range touches, whatever their
+// length in hours; an end after midnight (e.g. "since monday", which runs
+// until now) includes its partial last day.
+func reportDays(start, end time.Time) int {
+	days := civil.DateOf(end).DaysSince(civil.DateOf(start))
+	if !end.Equal(civil.DateOf(end).In(end.Location())) {
+		days++
+	}
+	return days
+}
+
+// reportOptions collects the config settings the build and render stages
+// need, validating them once up front.
+func reportOptions(cfg timewarrior.TimewConfig) (build.Options, render.Options, error) {
+	birthdayMonth, birthdayDay, err := cfg.Birthday()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	isoWeeks, err := cfg.ISOWeeks()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	weekStart, err := cfg.WeekStart()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+
+	ruleSet, err := rules.FromConfig(cfg)
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	allocation, err := cfg.Allocation()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	projectDepth, err := cfg.ProjectDepth()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	rounding, err := cfg.Rounding()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	policy, err := billing.FromConfig(cfg)
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	exportHours, err := cfg.ExportHours()
+	if err != nil {
+		return build.Options{}, render.Options{}, err
+	}
+	categories, fallback := cfg.Categories()
+
+	buildOpts := build.Options{
+		WeekStart:        weekStart,
+		ISOWeeks:         isoWeeks,
+		Rules:            ruleSet,
+		Allocation:       build.Allocation(allocation),
+		Categories:       categories,
+		FallbackCategory: fallback,
+		Billing:          policy,
+	}
+	renderOpts := render.Options{
+		BirthdayMonth: birthdayMonth,
+		BirthdayDay:   birthdayDay,
+		ISOWeeks:      isoWeeks,
+		Categories:    categories,
+		ProjectDepth:  projectDepth,
+		Dimensions:    cfg.Dimensions(),
+		Rounding:      render.Rounding(rounding),
+		ShowBilled:    policy != nil,
+		WeekStart:     weekStart,
+		ClockHours:    exportHours == "h:mm",
+	}
+	return buildOpts, renderOpts, nil
+}
+
 const (
 	formatMarkdown = "markdown"
 	formatColor    = "color"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatPDF      = "pdf"
+	formatJSON     = "json"
+	formatHTML     = "html"
+	formatSVG      = "svg"
+	formatPNG      = "png"
 )
 
 // resolveFormat picks the output format by precedence: LUME_FORMAT env var,
@@ -118,26 +349,215 @@ func resolveFormat(cfg timewarrior.TimewConfig) string {
 			return formatMarkdown
 		case formatColor:
 			return formatColor
+		case formatCSV:
+			return formatCSV
+		case formatTSV:
+			return formatTSV
+		case formatPDF:
+			return formatPDF
+		case formatJSON:
+			return formatJSON
+		case formatHTML:
+			return formatHTML
+		case formatSVG:
+			return formatSVG
+		case formatPNG:
+			return formatPNG
 		}
 	}
 	return formatColor
 }
 
+// delimiter returns the field separator of a delimited format: a tab for
+// tsv, else a comma.
+func delimiter(format string) rune {
+	if format == formatTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// chartFiles is where the svg and png formats write a report's charts: the
+// selected names, or every chart the report has when none are, into dir.
+type chartFiles struct {
+	format string
+	names  []string
+	dir    string
+}
+
+// resolveCharts reads the chart names from the LUME_CHARTS env var, else the
+// reports.lume.charts config key, and the directory from LUME_CHARTS_DIR,
+// else reports.lume.charts.dir, else the working directory.
+func resolveCharts(cfg timewarrior.TimewConfig, format string, opts render.Options) (chartFiles, error) {
+	v := strings.TrimSpace(os.Getenv("LUME_CHARTS"))
+	if v == "" {
+		v = cfg.Charts()
+	}
+	known := render.ChartNames(opts)
+	files := chartFiles{format: format}
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(known, name) {
+			return chartFiles{}, fmt.Errorf("invalid chart %q (use %s)", name, strings.Join(known, ", "))
+		}
+		files.names = append(files.names, name)
+	}
+
+	files.dir = strings.TrimSpace(os.Getenv("LUME_CHARTS_DIR"))
+	if files.dir == "" {
+		files.dir = cfg.ChartsDir()
+	}
+	if files.dir == "" {
+		files.dir = "."
+	}
+	return files, nil
+}
+
+// write saves each selected chart as <dir>/<name>.svg or .png and lists the
+// files it wrote. Selected charts the report does not have, such as the daily trend
+// of a month, are skipped.
+func (f chartFiles) write(charts []render.Chart) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	written := 0
+	for _, c := range charts {
+		if len(f.names) > 0 && !slices.Contains(f.names, c.Name) {
+			continue
+		}
+		path := filepath.Join(f.dir, c.Name+"."+f.format)
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if f.format == formatPNG {
+			err = render.WriteChartPNG(file, c)
+		} else {
+			err = render.WriteChartSVG(file, c)
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		written++
+	}
+	if written == 0 {
+		fmt.Println("No charts to write.")
+	}
+	return nil
+}
+
+const (
+	reportTime    = "time"
+	reportBilling = "billing"
+)
+
+// resolveReportKind picks the report kind from the LUME_REPORT env var, else
+// the reports.lume.report config key: time (the default) or billing.
+func resolveReportKind(cfg timewarrior.TimewConfig) (string, error) {
+	v := strings.TrimSpace(os.Getenv("LUME_REPORT"))
+	if v == "" {
+		v = cfg.Report()
+	}
+	switch kind := strings.ToLower(v); kind {
+	case "", reportTime:
+		return reportTime, nil
+	case reportBilling:
+		return reportBilling, nil
+	}
+	return "", fmt.Errorf("invalid report %q (use time or billing)", v)
+}
+
+// resolvePivot returns the row and column dimensions of a pivot report from
+// the LUME_PIVOT env var, else the reports.lume.pivot config key, both given
+// as "rows,columns" (e.g. "client,week"). Empty dimensions mean no pivot.
+// Each must be built in, listed in reports.lume.dimensions or the key of a
+// key:value tag found in entries.
+func resolvePivot(cfg timewarrior.TimewConfig, entries []timewarrior.Entry, opts build.Options) (string, string, error) {
+	v := strings.TrimSpace(os.Getenv("LUME_PIVOT"))
+	if v == "" {
+		v = cfg.Pivot()
+	}
+	if v == "" {
+		return "", "", nil
+	}
+	rowBy, columnBy, ok := strings.Cut(strings.ToLower(v), ",")
+	rowBy, columnBy = strings.TrimSpace(rowBy), strings.TrimSpace(columnBy)
+	if !ok || rowBy == "" || columnBy == "" {
+		return "", "", fmt.Errorf("invalid pivot %q (use rows,columns such as client,week)", v)
+	}
+	dimensions := build.PivotDimensions(entries, opts)
+	for _, name := range cfg.Dimensions() {
+		if !slices.Contains(dimensions, name) {
+			dimensions = append(dimensions, name)
+		}
+	}
+	for _, by := range []string{rowBy, columnBy} {
+		if !slices.Contains(dimensions, by) {
+			return "", "", fmt.Errorf("invalid pivot dimension %q (use %s)", by, strings.Join(dimensions, ", "))
+		}
+	}
+	return rowBy, columnBy, nil
+}
+
+// resolveFilter parses the report filter expression from the LUME_FILTER env
+// var, else the reports.lume.filter config key. A nil expression keeps every
+// entry.
+func resolveFilter(cfg timewarrior.TimewConfig) (filter.Expr, error) {
+	v := strings.TrimSpace(os.Getenv("LUME_FILTER"))
+	if v == "" {
+		v = cfg.Filter()
+	}
+	expr, err := filter.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", v, err)
+	}
+	return expr, nil
+}
+
+// loadAllEntries reads every interval from the data directory, bypassing
+// timew's export, and re-applies the report filter timew would have used so
+// the result only differs from the export by the range.
 func loadAllEntries(cfg timewarrior.TimewConfig) ([]timewarrior.Entry, error) {
 	dataDir := resolveDataDir(cfg)
 	if dataDir == "" {
 		return nil, fmt.Errorf("cannot determine timewarrior data directory")
 	}
-	return timewarrior.ParseDataDir(dataDir)
+	entries, err := timewarrior.ParseDataDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return filterEntries(cfg, entries), nil
+}
+
+// filterEntries keeps the entries that pass the report's filter: every tag
+// in temp.report.tags must be present.
+func filterEntries(cfg timewarrior.TimewConfig, entries []timewarrior.Entry) []timewarrior.Entry {
+	tags := cfg.ReportTags()
+	if len(tags) == 0 {
+		return entries
+	}
+	var filtered []timewarrior.Entry
+	for _, e := range entries {
+		if e.HasTags(tags) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
 }
 
 func resolveDataDir(cfg timewarrior.TimewConfig) string {
 	if db := cfg.Get("temp.db"); db != "" {
 		return filepath.Join(db, "data")
 	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
+	if db := timewarrior.DatabaseDir(); db != "" {
+		return filepath.Join(db, "data")
 	}
-	return filepath.Join(home, ".config", "timewarrior", "data")
+	return ""
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+func TestReportDaysAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone       string
+		start, end string // local times; the end is exclusive
+		want       int
+	}{
+		{"America/New_York", "2025-03-09 00:00", "2025-03-10 00:00", 1}, // 23 hours
+		{"America/New_York", "2025-03-09 00:00", "2025-03-16 00:00", 7}, // 167 hours
+		{"America/New_York", "2025-11-02 00:00", "2025-11-03 00:00", 1}, // 25 hours
+		{"America/New_York", "2025-11-02 00:00", "2025-11-09 00:00", 7},
+		{"America/New_York", "2025-03-09 00:00", "2025-03-11 10:00", 3}, // partial last day
+		{"Europe/Berlin", "2025-03-30 00:00", "2025-03-31 00:00", 1},
+		{"Europe/Berlin", "2025-03-24 00:00", "2025-03-31 00:00", 7},
+		{"Europe/Berlin", "2025-10-26 00:00", "2025-10-27 00:00", 1},
+		{"Europe/Berlin", "2025-10-20 00:00", "2025-10-27 00:00", 7},
+		{"Europe/Berlin", "2025-10-01 00:00", "2025-11-01 00:00", 31},
+		{"Australia/Sydney", "2025-04-06 00:00", "2025-04-07 00:00", 1},
+		{"Australia/Sydney", "2025-04-06 00:00", "2025-04-13 00:00", 7},
+		{"Australia/Sydney", "2025-10-05 00:00", "2025-10-06 00:00", 1},
+		{"Australia/Sydney", "2025-10-05 00:00", "2025-10-12 00:00", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.start+" "+tt.end, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, _ := time.ParseInLocation("2006-01-02 15:04", tt.start, loc)
+			end, _ := time.ParseInLocation("2006-01-02 15:04", tt.end, loc)
+			if got := reportDays(start, end); got != tt.want {
+				t.Errorf("reportDays = %d, want %d (%v)", got, tt.want, end.Sub(start))
+			}
+		})
+	}
+}
+
+func TestReportOptionsBilling(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]string
+		showBilled bool
+		wantErr    string
+	}{
+		{"no billing keys", map[string]string{"reports.lume.format": "markdown"}, false, ""},
+		{"rate", map[string]string{"reports.lume.billing.rate": "90"}, true, ""},
+		{"zero rate", map[string]string{"reports.lume.billing.rate": "0", "reports.lume.billing.increment": "15m"}, true, ""},
+		{"increment without a rate", map[string]string{"reports.lume.billing.increment": "15m"}, false, "missing reports.lume.billing.rate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildOpts, renderOpts, err := reportOptions(timewarrior.TimewConfig{Values: tt.values})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("reportOptions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if renderOpts.ShowBilled != tt.showBilled || (buildOpts.Billing != nil) != tt.showBilled {
+				t.Errorf("ShowBilled = %t, Billing = %+v, want billing %t", renderOpts.ShowBilled, buildOpts.Billing, tt.showBilled)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amiraminb/lume/internal/daterange"
+	"github.com/amiraminb/lume/internal/timewarrior"
+)
+
+const usage = `usage: lume report [range] [tag ...]
+
+range is a timewarrior hint (:day, :yesterday, :week, :lastweek, :fortnight,
+:month, :lastmonth, :quarter, :year, :ytd, :all, ...) or a span given as
+separate, unquoted words, such as 2025-01-01 - 2025-02-01,
+from 2025-01-01 to 2025-02-01 or since monday. It defaults to :day.`
+
+// runStandalone handles direct invocations (lume report :week) where timew is
+// not piping its config and export on stdin. It reads the data directory
+// itself and builds the same TimewConfig timew would have sent, so the rest
+// of the pipeline cannot tell the two modes apart.
+func runStandalone(args []string) error {
+	if args[0] != "report" {
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+
+	cfg, err := timewarrior.LoadConfig()
+	if err != nil {
+		return err
+	}
+	weekStart, err := cfg.WeekStart()
+	if err != nil {
+		return err
+	}
+
+	r, tags, err := daterange.Parse(args[1:], time.Now(), weekStart)
+	if err != nil {
+		return err
+	}
+	if !r.IsZero() {
+		cfg.Values["temp.report.start"] = r.Start.UTC().Format("20060102T150405Z")
+		cfg.Values["temp.report.end"] = r.End.UTC().Format("20060102T150405Z")
+	}
+	if len(tags) > 0 {
+		cfg.Values["temp.report.tags"] = strings.Join(tags, ",")
+	}
+
+	allEntries, err := loadAllEntries(cfg)
+	if err != nil {
+		return err
+	}
+
+	// loadAllEntries already applied the tag filter; narrowing to intervals
+	// that overlap the range completes what timew would have exported.
+	var entries []timewarrior.Entry
+	for _, e := range allEntries {
+		if r.IsZero() || (e.End.After(r.Start) && e.Start.Before(r.End)) {
+			entries = append(entries, e)
+		}
+	}
+
+	return runReport(cfg, entries)
+}